@@ -1,6 +1,8 @@
 package project
 
 import (
+	"fmt"
+
 	"github.com/MakeNowJust/heredoc"
 	cmdClose "github.com/cli/cli/v2/pkg/cmd/project/close"
 	cmdCopy "github.com/cli/cli/v2/pkg/cmd/project/copy"
@@ -18,7 +20,10 @@ import (
 	cmdItemList "github.com/cli/cli/v2/pkg/cmd/project/item-list"
 	cmdLink "github.com/cli/cli/v2/pkg/cmd/project/link"
 	cmdList "github.com/cli/cli/v2/pkg/cmd/project/list"
-	cmdTemplate "github.com/cli/cli/v2/pkg/cmd/project/mark-template"
+	cmdMarkTemplate "github.com/cli/cli/v2/pkg/cmd/project/mark-template"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/client"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	cmdTemplate "github.com/cli/cli/v2/pkg/cmd/project/template"
 	cmdUnlink "github.com/cli/cli/v2/pkg/cmd/project/unlink"
 	cmdView "github.com/cli/cli/v2/pkg/cmd/project/view"
 	"github.com/cli/cli/v2/pkg/cmdutil"
@@ -26,6 +31,9 @@ import (
 )
 
 func NewCmdProject(f *cmdutil.Factory) *cobra.Command {
+	var assumeOwnerType string
+	var userAgent string
+
 	var cmd = &cobra.Command{
 		Use:   "project <command>",
 		Short: "Work with GitHub Projects.",
@@ -37,8 +45,25 @@ func NewCmdProject(f *cmdutil.Factory) *cobra.Command {
 			$ gh project item-list 1 --owner cli
 		`),
 		GroupID: "core",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			switch assumeOwnerType {
+			case "":
+				client.AssumedOwnerType = ""
+			case "user":
+				client.AssumedOwnerType = queries.UserOwner
+			case "org":
+				client.AssumedOwnerType = queries.OrgOwner
+			default:
+				return fmt.Errorf("invalid value %q for --assume-owner-type, must be one of \"user\" or \"org\"", assumeOwnerType)
+			}
+			client.UserAgentOverride = userAgent
+			return nil
+		},
 	}
 
+	cmd.PersistentFlags().StringVar(&assumeOwnerType, "assume-owner-type", "", "Assume all `--owner` logins in this run are of this type (\"user\" or \"org\"), skipping the owner-type detection query")
+	cmd.PersistentFlags().StringVar(&userAgent, "user-agent", "", "Override the `User-Agent` header sent on every GraphQL request, for ops teams that allowlist or attribute traffic by it. Defaults to the standard gh User-Agent, or to GH_PROJECT_USER_AGENT if set and this flag is not.")
+
 	cmd.AddCommand(cmdList.NewCmdList(f, nil))
 	cmd.AddCommand(cmdCreate.NewCmdCreate(f, nil))
 	cmd.AddCommand(cmdCopy.NewCmdCopy(f, nil))
@@ -47,7 +72,8 @@ func NewCmdProject(f *cmdutil.Factory) *cobra.Command {
 	cmd.AddCommand(cmdEdit.NewCmdEdit(f, nil))
 	cmd.AddCommand(cmdLink.NewCmdLink(f, nil))
 	cmd.AddCommand(cmdView.NewCmdView(f, nil))
-	cmd.AddCommand(cmdTemplate.NewCmdMarkTemplate(f, nil))
+	cmd.AddCommand(cmdMarkTemplate.NewCmdMarkTemplate(f, nil))
+	cmd.AddCommand(cmdTemplate.NewCmdTemplate(f, nil))
 	cmd.AddCommand(cmdUnlink.NewCmdUnlink(f, nil))
 
 	// items
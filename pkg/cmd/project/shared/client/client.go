@@ -1,12 +1,34 @@
 package client
 
 import (
+	"net/http"
 	"os"
 
 	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
 	"github.com/cli/cli/v2/pkg/cmdutil"
 )
 
+// RoundTripper, when set, wraps the transport used for all project GraphQL
+// requests built by New. Tests use it to intercept requests without a real
+// network round trip; it can also be set by callers that need to present a
+// client certificate (mTLS) to an enterprise GraphQL endpoint that requires
+// one.
+var RoundTripper func(http.RoundTripper) http.RoundTripper
+
+// AssumedOwnerType, when set by the `project` command's --assume-owner-type
+// persistent flag, is applied to every client New builds for the lifetime of
+// the process, so every project subcommand invoked in a run skips owner-type
+// detection rather than just the one that set it.
+var AssumedOwnerType queries.OwnerType
+
+// UserAgentOverride, when set by the `project` command's --user-agent
+// persistent flag (or the GH_PROJECT_USER_AGENT environment variable, used
+// when the flag is unset), replaces the standard `gh` User-Agent on every
+// GraphQL request a client New builds makes, so an ops team can allowlist or
+// attribute traffic from their own automation. The flag takes precedence
+// over the environment variable.
+var UserAgentOverride string
+
 func New(f *cmdutil.Factory) (*queries.Client, error) {
 	if f.HttpClient == nil {
 		// This is for compatibility with tests that exercise Cobra command functionality.
@@ -18,5 +40,46 @@ func New(f *cmdutil.Factory) (*queries.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return queries.NewClient(httpClient, os.Getenv("GH_HOST"), f.IOStreams), nil
+
+	if RoundTripper != nil {
+		httpClient.Transport = RoundTripper(httpClient.Transport)
+	}
+
+	if userAgent := userAgentOverride(); userAgent != "" {
+		httpClient.Transport = userAgentTransport{base: httpClient.Transport, userAgent: userAgent}
+	}
+
+	c := queries.NewClient(httpClient, os.Getenv("GH_HOST"), f.IOStreams)
+	if AssumedOwnerType != "" {
+		c.SetAssumedOwnerType(AssumedOwnerType)
+	}
+	return c, nil
+}
+
+// userAgentOverride resolves the effective User-Agent override, preferring
+// --user-agent over GH_PROJECT_USER_AGENT.
+func userAgentOverride() string {
+	if UserAgentOverride != "" {
+		return UserAgentOverride
+	}
+	return os.Getenv("GH_PROJECT_USER_AGENT")
+}
+
+// userAgentTransport sets the User-Agent header on every outgoing request,
+// overriding whatever the underlying transport or client would otherwise
+// send.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
 }
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+// capturingTransport records the User-Agent of every request it sees and
+// replies with a minimal, well-formed GraphQL response.
+type capturingTransport struct {
+	gotUserAgent string
+}
+
+func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.gotUserAgent = req.Header.Get("User-Agent")
+	body := `{"data":{"rateLimit":{"remaining":5000,"resetAt":"2024-01-01T00:00:00Z"}}}`
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestNew_UserAgentOverride(t *testing.T) {
+	tests := []struct {
+		name          string
+		override      string
+		env           string
+		wantUserAgent string
+	}{
+		{name: "no override", wantUserAgent: ""},
+		{name: "flag override", override: "ops-automation/1.0", wantUserAgent: "ops-automation/1.0"},
+		{name: "env override", env: "ops-automation-env/1.0", wantUserAgent: "ops-automation-env/1.0"},
+		{name: "flag takes precedence over env", override: "ops-automation/1.0", env: "ops-automation-env/1.0", wantUserAgent: "ops-automation/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UserAgentOverride = tt.override
+			t.Cleanup(func() { UserAgentOverride = "" })
+			if tt.env != "" {
+				t.Setenv("GH_PROJECT_USER_AGENT", tt.env)
+			}
+
+			transport := &capturingTransport{}
+			f := &cmdutil.Factory{
+				HttpClient: func() (*http.Client, error) {
+					return &http.Client{Transport: transport}, nil
+				},
+				IOStreams: iostreams.System(),
+			}
+
+			c, err := New(f)
+			assert.NoError(t, err)
+
+			_, err = c.RateLimit()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUserAgent, transport.gotUserAgent)
+		})
+	}
+}
@@ -126,6 +126,26 @@ func TestJSONProjectField_SingleSelectType(t *testing.T) {
 	assert.JSONEq(t, `{"id":"123","name":"name","type":"ProjectV2SingleSelectField","options":[{"id":"123","name":"name"},{"id":"456","name":"name2"}]}`, string(b))
 }
 
+func TestJSONProjectField_SingleSelectType_OptionColorAndDescription(t *testing.T) {
+	field := ProjectField{}
+	field.TypeName = "ProjectV2SingleSelectField"
+	field.SingleSelectField.ID = "123"
+	field.SingleSelectField.Name = "name"
+	field.SingleSelectField.Options = []SingleSelectFieldOptions{
+		{
+			ID:          "123",
+			Name:        "name",
+			Color:       "BLUE",
+			Description: "a description",
+		},
+	}
+
+	b, err := json.Marshal(field.ExportData(nil))
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{"id":"123","name":"name","type":"ProjectV2SingleSelectField","options":[{"id":"123","name":"name","color":"BLUE","description":"a description"}]}`, string(b))
+}
+
 func TestJSONProjectField_ProjectV2IterationField(t *testing.T) {
 	field := ProjectField{}
 	field.TypeName = "ProjectV2IterationField"
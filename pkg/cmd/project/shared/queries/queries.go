@@ -1,6 +1,7 @@
 package queries
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -73,15 +74,23 @@ func (c *hostScopedClient) Mutate(queryName string, query interface{}, variables
 	return c.Client.Mutate(c.hostname, queryName, query, variables)
 }
 
+func (c *hostScopedClient) QueryWithContext(ctx context.Context, queryName string, query interface{}, variables map[string]interface{}) error {
+	return c.Client.QueryWithContext(ctx, c.hostname, queryName, query, variables)
+}
+
 type graphqlClient interface {
 	Query(queryName string, query interface{}, variables map[string]interface{}) error
 	Mutate(queryName string, query interface{}, variables map[string]interface{}) error
+	QueryWithContext(ctx context.Context, queryName string, query interface{}, variables map[string]interface{}) error
 }
 
 type Client struct {
 	apiClient graphqlClient
 	spinner   bool
 	prompter  iprompter
+
+	templateCapabilities *TemplateCapabilities
+	assumedOwnerType     OwnerType
 }
 
 const (
@@ -105,6 +114,23 @@ func (c *Client) doQuery(name string, query interface{}, variables map[string]in
 	return handleError(err)
 }
 
+// doQueryWithContext is doQuery with a caller-supplied context, for queries
+// that need to be individually cancelable, e.g. a per-attempt deadline
+// during --verify retries.
+func (c *Client) doQueryWithContext(ctx context.Context, name string, query interface{}, variables map[string]interface{}) error {
+	var sp *spinner.Spinner
+	if c.spinner {
+		dotStyle := spinner.CharSets[11]
+		sp = spinner.New(dotStyle, 120*time.Millisecond, spinner.WithColor("fgCyan"))
+		sp.Start()
+	}
+	err := c.apiClient.QueryWithContext(ctx, name, query, variables)
+	if sp != nil {
+		sp.Stop()
+	}
+	return handleError(err)
+}
+
 // TODO: un-export this since it couples the caller heavily to api.GraphQLClient
 func (c *Client) Mutate(operationName string, query interface{}, variables map[string]interface{}) error {
 	err := c.apiClient.Mutate(operationName, query, variables)
@@ -766,16 +792,40 @@ func (p ProjectField) Type() string {
 	return p.TypeName
 }
 
+// DataType is the ProjectV2FieldType of the project field, e.g. "TEXT" or
+// "SINGLE_SELECT" for a custom field, or a built-in type like "ASSIGNEES"
+// or "ITERATION" for one that isn't.
+func (p ProjectField) DataType() string {
+	if p.TypeName == "ProjectV2Field" {
+		return p.Field.DataType
+	} else if p.TypeName == "ProjectV2IterationField" {
+		return p.IterationField.DataType
+	} else if p.TypeName == "ProjectV2SingleSelectField" {
+		return p.SingleSelectField.DataType
+	}
+	return ""
+}
+
 type SingleSelectFieldOptions struct {
-	ID   string
-	Name string
+	ID          string
+	Name        string
+	Color       string
+	Description string
 }
 
 func (f SingleSelectFieldOptions) ExportData(_ []string) map[string]interface{} {
-	return map[string]interface{}{
+	v := map[string]interface{}{
 		"id":   f.ID,
 		"name": f.Name,
 	}
+	// Emulate omitempty
+	if f.Color != "" {
+		v["color"] = f.Color
+	}
+	if f.Description != "" {
+		v["description"] = f.Description
+	}
+	return v
 }
 
 func (p ProjectField) Options() []SingleSelectFieldOptions {
@@ -783,8 +833,10 @@ func (p ProjectField) Options() []SingleSelectFieldOptions {
 		var options []SingleSelectFieldOptions
 		for _, o := range p.SingleSelectField.Options {
 			options = append(options, SingleSelectFieldOptions{
-				ID:   o.ID,
-				Name: o.Name,
+				ID:          o.ID,
+				Name:        o.Name,
+				Color:       o.Color,
+				Description: o.Description,
 			})
 		}
 		return options
@@ -983,6 +1035,297 @@ func (c *Client) ViewerLoginName() (string, error) {
 	return query.Viewer.Login, nil
 }
 
+// TemplateCapabilities describes which template-related schema features the
+// current host supports. Older GHES versions may not expose every template
+// mutation or query field, so callers should probe for them rather than
+// assume parity with github.com.
+type TemplateCapabilities struct {
+	Templates       bool `json:"templates"`
+	TemplateGallery bool `json:"templateGallery"`
+	Derivatives     bool `json:"derivatives"`
+	SourceTemplate  bool `json:"sourceTemplate"`
+}
+
+// ProbeTemplateCapabilities runs a schema introspection query to determine
+// which template-related features the host supports. The result is cached
+// for the lifetime of the client, since the schema does not change between
+// calls made during a single invocation.
+func (c *Client) ProbeTemplateCapabilities() (TemplateCapabilities, error) {
+	if c.templateCapabilities != nil {
+		return *c.templateCapabilities, nil
+	}
+
+	var query struct {
+		Mutation struct {
+			Fields []struct {
+				Name string
+			}
+		} `graphql:"Mutation: __type(name: \"Mutation\")"`
+		Organization struct {
+			Fields []struct {
+				Name string
+			} `graphql:"fields(includeDeprecated: true)"`
+		} `graphql:"Organization: __type(name: \"Organization\")"`
+		ProjectV2 struct {
+			Fields []struct {
+				Name string
+			} `graphql:"fields(includeDeprecated: true)"`
+		} `graphql:"ProjectV2: __type(name: \"ProjectV2\")"`
+	}
+
+	if err := c.doQuery("ProjectTemplate_capabilities", &query, nil); err != nil {
+		return TemplateCapabilities{}, err
+	}
+
+	caps := TemplateCapabilities{}
+	for _, f := range query.Mutation.Fields {
+		if f.Name == "markProjectV2AsTemplate" {
+			caps.Templates = true
+		}
+	}
+	for _, f := range query.Organization.Fields {
+		if f.Name == "projectV2Templates" {
+			caps.TemplateGallery = true
+		}
+	}
+	for _, f := range query.ProjectV2.Fields {
+		switch f.Name {
+		case "derivedProjects":
+			caps.Derivatives = true
+		case "sourceTemplate":
+			caps.SourceTemplate = true
+		}
+	}
+
+	c.templateCapabilities = &caps
+	return caps, nil
+}
+
+// RateLimit is the state of the token's primary GraphQL rate limit as of the
+// most recent RateLimit call.
+type RateLimit struct {
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RateLimit queries the primary GraphQL rate limit, so a caller can detect
+// exhaustion ahead of an operation instead of letting it fail with GitHub's
+// rate-limit error partway through.
+func (c *Client) RateLimit() (RateLimit, error) {
+	var query struct {
+		RateLimit struct {
+			Remaining int
+			ResetAt   time.Time
+		} `graphql:"rateLimit"`
+	}
+
+	if err := c.doQuery("RateLimit", &query, nil); err != nil {
+		return RateLimit{}, err
+	}
+
+	return RateLimit{Remaining: query.RateLimit.Remaining, ResetAt: query.RateLimit.ResetAt}, nil
+}
+
+// EnterpriseSupported reports whether the host's GraphQL schema exposes the
+// top-level enterprise query field, which is unavailable on GHES hosts that
+// do not support enterprise-managed organizations.
+func (c *Client) EnterpriseSupported() (bool, error) {
+	var query struct {
+		Query struct {
+			Fields []struct {
+				Name string
+			}
+		} `graphql:"Query: __type(name: \"Query\")"`
+	}
+
+	if err := c.doQuery("Enterprise_capabilities", &query, nil); err != nil {
+		return false, err
+	}
+
+	for _, f := range query.Query.Fields {
+		if f.Name == "enterprise" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// enterpriseOrgs is the result of a single page of an enterprise's member
+// organizations.
+type enterpriseOrgs struct {
+	Enterprise struct {
+		Organizations struct {
+			PageInfo PageInfo
+			Nodes    []struct {
+				Login string
+				ID    string
+			}
+		} `graphql:"organizations(first: 100, after: $after)"`
+	} `graphql:"enterprise(slug: $slug)"`
+}
+
+// enterpriseOrgLogins gets the logins of every organization belonging to
+// the enterprise identified by slug.
+func (c *Client) enterpriseOrgLogins(slug string) ([]loginTypes, error) {
+	l := make([]loginTypes, 0)
+	var v enterpriseOrgs
+	variables := map[string]interface{}{
+		"slug":  githubv4.String(slug),
+		"after": (*githubv4.String)(nil),
+	}
+
+	err := c.doQuery("EnterpriseOrgs", &v, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, org := range v.Enterprise.Organizations.Nodes {
+		l = append(l, loginTypes{Login: org.Login, Type: OrgOwner, ID: org.ID})
+	}
+
+	if v.Enterprise.Organizations.PageInfo.HasNextPage {
+		return c.paginateEnterpriseOrgLogins(l, slug, string(v.Enterprise.Organizations.PageInfo.EndCursor))
+	}
+
+	return l, nil
+}
+
+// paginateEnterpriseOrgLogins fetches the logins after cursor and appends
+// them to the list of logins.
+func (c *Client) paginateEnterpriseOrgLogins(l []loginTypes, slug, cursor string) ([]loginTypes, error) {
+	var v enterpriseOrgs
+	variables := map[string]interface{}{
+		"slug":  githubv4.String(slug),
+		"after": githubv4.String(cursor),
+	}
+
+	err := c.doQuery("EnterpriseOrgs", &v, variables)
+	if err != nil {
+		return l, err
+	}
+
+	for _, org := range v.Enterprise.Organizations.Nodes {
+		l = append(l, loginTypes{Login: org.Login, Type: OrgOwner, ID: org.ID})
+	}
+
+	if v.Enterprise.Organizations.PageInfo.HasNextPage {
+		return c.paginateEnterpriseOrgLogins(l, slug, string(v.Enterprise.Organizations.PageInfo.EndCursor))
+	}
+
+	return l, nil
+}
+
+// EnterpriseOrgs returns the organizations that belong to the enterprise
+// identified by slug, for fleet-wide batch operations that must run across
+// every org an enterprise admin manages (e.g. template --enterprise). It
+// returns a clear error if the host's schema does not expose enterprise
+// queries at all, rather than letting the query itself fail with an opaque
+// GraphQL error.
+func (c *Client) EnterpriseOrgs(slug string) ([]Owner, error) {
+	supported, err := c.EnterpriseSupported()
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, fmt.Errorf("this host's GraphQL schema does not support enterprise-scoped queries")
+	}
+
+	logins, err := c.enterpriseOrgLogins(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make([]Owner, 0, len(logins))
+	for _, l := range logins {
+		orgs = append(orgs, Owner{Login: l.Login, Type: l.Type, ID: l.ID})
+	}
+	return orgs, nil
+}
+
+// ProjectSearchSupported reports whether the host's GraphQL schema's
+// SearchType enum has a PROJECT value, letting the top-level search query
+// find ProjectV2 nodes directly. As of this writing no github.com or GHES
+// release actually exposes one — SearchType's values are REPOSITORY, ISSUE,
+// DISCUSSION, and USER — so this probe exists to fail --search clearly if
+// it's ever run against a host that predates that, rather than to support a
+// feature that exists today.
+func (c *Client) ProjectSearchSupported() (bool, error) {
+	var query struct {
+		SearchType struct {
+			EnumValues []struct {
+				Name string
+			} `graphql:"enumValues(includeDeprecated: true)"`
+		} `graphql:"SearchType: __type(name: \"SearchType\")"`
+	}
+
+	if err := c.doQuery("ProjectSearch_capabilities", &query, nil); err != nil {
+		return false, err
+	}
+
+	for _, v := range query.SearchType.EnumValues {
+		if v.Name == "PROJECT" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// searchProjects is a single page of a PROJECT-scoped search query.
+type searchProjects struct {
+	Search struct {
+		Nodes []struct {
+			Project Project `graphql:"... on ProjectV2"`
+		}
+		PageInfo PageInfo
+	} `graphql:"search(query: $query, type: PROJECT, first: $first, after: $after)"`
+}
+
+// SearchProjects runs a PROJECT-scoped search for searchQuery and returns
+// every matching ProjectV2, paginating until the search is exhausted. It
+// returns an error if the host's GraphQL schema does not support
+// PROJECT-scoped search (see ProjectSearchSupported).
+func (c *Client) SearchProjects(searchQuery string) ([]Project, error) {
+	supported, err := c.ProjectSearchSupported()
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, fmt.Errorf("this host's GraphQL schema does not support searching for projects")
+	}
+
+	projects := make([]Project, 0)
+	variables := map[string]interface{}{
+		"query": githubv4.String(searchQuery),
+		"first": githubv4.Int(LimitMax),
+		"after": (*githubv4.String)(nil),
+	}
+
+	for {
+		var query searchProjects
+		if err := c.doQuery("SearchProjects", &query, variables); err != nil {
+			return nil, err
+		}
+
+		for _, n := range query.Search.Nodes {
+			projects = append(projects, n.Project)
+		}
+
+		if !query.Search.PageInfo.HasNextPage {
+			return projects, nil
+		}
+		variables["after"] = githubv4.String(query.Search.PageInfo.EndCursor)
+	}
+}
+
+// SetAssumedOwnerType configures the client to skip the owner-type detection
+// query in OwnerIDAndType (and therefore NewOwner) and assume every login is
+// of type t. This trades the extra detection round-trip for a clear error if
+// a login turns out not to actually be of that type. It has no effect on the
+// "@me"/empty login, which is always resolved as the viewer.
+func (c *Client) SetAssumedOwnerType(t OwnerType) {
+	c.assumedOwnerType = t
+}
+
 // OwnerIDAndType returns the ID and OwnerType. The special login "@me" or an empty string queries the current user.
 func (c *Client) OwnerIDAndType(login string) (string, OwnerType, error) {
 	if login == "@me" || login == "" {
@@ -994,6 +1337,10 @@ func (c *Client) OwnerIDAndType(login string) (string, OwnerType, error) {
 		return query.Viewer.Id, ViewerOwner, nil
 	}
 
+	if c.assumedOwnerType != "" {
+		return c.ownerIDForAssumedType(login)
+	}
+
 	variables := map[string]interface{}{
 		"login": githubv4.String(login),
 	}
@@ -1028,6 +1375,45 @@ func (c *Client) OwnerIDAndType(login string) (string, OwnerType, error) {
 	return "", "", errors.New("unknown owner type")
 }
 
+// ownerIDForAssumedType looks up login's ID using only the single schema
+// field named by c.assumedOwnerType, instead of OwnerIDAndType's normal
+// query-both-and-disambiguate approach. If login does not actually exist as
+// that type, GitHub returns a NOT_FOUND error for the field queried, which
+// is wrapped here into a clear mis-specification error rather than the
+// generic "unknown owner type".
+func (c *Client) ownerIDForAssumedType(login string) (string, OwnerType, error) {
+	variables := map[string]interface{}{
+		"login": githubv4.String(login),
+	}
+
+	switch c.assumedOwnerType {
+	case OrgOwner:
+		var query struct {
+			Organization struct {
+				Id string
+			} `graphql:"organization(login: $login)"`
+		}
+		if err := c.doQuery("AssumedOrgOwner", &query, variables); err != nil {
+			return "", "", fmt.Errorf("--assume-owner-type org: %q is not an organization: %w", login, err)
+		}
+		return query.Organization.Id, OrgOwner, nil
+
+	case UserOwner:
+		var query struct {
+			User struct {
+				Id string
+			} `graphql:"user(login: $login)"`
+		}
+		if err := c.doQuery("AssumedUserOwner", &query, variables); err != nil {
+			return "", "", fmt.Errorf("--assume-owner-type user: %q is not a user: %w", login, err)
+		}
+		return query.User.Id, UserOwner, nil
+
+	default:
+		return "", "", fmt.Errorf("unsupported assumed owner type %q", c.assumedOwnerType)
+	}
+}
+
 // issueOrPullRequest is used to query the global id of an issue or pull request by its URL.
 type issueOrPullRequest struct {
 	Resource struct {
@@ -1144,6 +1530,110 @@ func (c *Client) userOrgLogins() ([]loginTypes, error) {
 	return l, nil
 }
 
+// ViewerOrgs returns the organizations the viewer can create projects in, for
+// bulk operations that need to run against every org the viewer belongs to
+// (e.g. template's --all-orgs), rather than prompting for a single owner.
+func (c *Client) ViewerOrgs() ([]Owner, error) {
+	logins, err := c.userOrgLogins()
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make([]Owner, 0, len(logins))
+	for _, l := range logins {
+		if l.Type == OrgOwner {
+			orgs = append(orgs, Owner{Login: l.Login, Type: l.Type, ID: l.ID})
+		}
+	}
+	return orgs, nil
+}
+
+// ViewerMemberOrgs returns every organization the viewer is a member of,
+// regardless of whether they can create projects there. This is a superset
+// of ViewerOrgs, meant for read-oriented batch operations (e.g. template
+// --my-orgs) where a contributor wants to audit or list across every org
+// they belong to, not just the ones they can write to. Callers doing a
+// write operation across these orgs should expect some of them to fail with
+// a permission error and handle that per-org, the way runOrgsBatch already
+// does, rather than assuming ViewerOrgs-level access.
+func (c *Client) ViewerMemberOrgs() ([]Owner, error) {
+	logins, err := c.memberOrgLogins()
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make([]Owner, 0, len(logins))
+	for _, l := range logins {
+		if l.Type == OrgOwner {
+			orgs = append(orgs, Owner{Login: l.Login, Type: l.Type, ID: l.ID})
+		}
+	}
+	return orgs, nil
+}
+
+// memberOrgLogins gets all the logins of the viewer and every organization
+// the viewer is a member of, without filtering by ViewerCanCreateProjects.
+func (c *Client) memberOrgLogins() ([]loginTypes, error) {
+	l := make([]loginTypes, 0)
+	var v viewerLoginOrgs
+	variables := map[string]interface{}{
+		"after": (*githubv4.String)(nil),
+	}
+
+	err := c.doQuery("ViewerLoginAndOrgs", &v, variables)
+	if err != nil {
+		return l, err
+	}
+
+	l = append(l, loginTypes{
+		Login: v.Viewer.Login,
+		Type:  ViewerOwner,
+		ID:    v.Viewer.ID,
+	})
+
+	for _, org := range v.Viewer.Organizations.Nodes {
+		l = append(l, loginTypes{
+			Login: org.Login,
+			Type:  OrgOwner,
+			ID:    org.ID,
+		})
+	}
+
+	if v.Viewer.Organizations.PageInfo.HasNextPage {
+		return c.paginateMemberOrgLogins(l, string(v.Viewer.Organizations.PageInfo.EndCursor))
+	}
+
+	return l, nil
+}
+
+// paginateMemberOrgLogins fetches the logins after cursor and appends them
+// to the list of logins, without filtering by ViewerCanCreateProjects.
+func (c *Client) paginateMemberOrgLogins(l []loginTypes, cursor string) ([]loginTypes, error) {
+	var v viewerLoginOrgs
+	variables := map[string]interface{}{
+		"after": githubv4.String(cursor),
+	}
+
+	err := c.doQuery("ViewerLoginAndOrgs", &v, variables)
+	if err != nil {
+		return l, err
+	}
+
+	for _, org := range v.Viewer.Organizations.Nodes {
+		l = append(l, loginTypes{
+			Login: org.Login,
+			Type:  OrgOwner,
+			ID:    org.ID,
+		})
+	}
+
+	if v.Viewer.Organizations.PageInfo.HasNextPage {
+		return c.paginateMemberOrgLogins(l, string(v.Viewer.Organizations.PageInfo.EndCursor))
+	}
+
+	return l, nil
+}
+
 // paginateOrgLogins after cursor and append them to the list of logins.
 func (c *Client) paginateOrgLogins(l []loginTypes, cursor string) ([]loginTypes, error) {
 	var v viewerLoginOrgs
@@ -1288,6 +1778,78 @@ func (c *Client) NewProject(canPrompt bool, o *Owner, number int32, fields bool)
 	return &projects.Nodes[answerIndex], nil
 }
 
+// NewProjectWithContext looks up a single project by number, like NewProject,
+// but bounds the request with ctx instead of always running to completion.
+// Unlike NewProject it never falls back to an interactive picker, since the
+// only caller (the --verify retry loop) always already knows the number.
+func (c *Client) NewProjectWithContext(ctx context.Context, o *Owner, number int32) (*Project, error) {
+	variables := map[string]interface{}{
+		"number":      githubv4.Int(number),
+		"firstItems":  githubv4.Int(0),
+		"afterItems":  (*githubv4.String)(nil),
+		"firstFields": githubv4.Int(0),
+		"afterFields": (*githubv4.String)(nil),
+	}
+
+	if o.Type == UserOwner {
+		var query userOwner
+		variables["login"] = githubv4.String(o.Login)
+		err := c.doQueryWithContext(ctx, "UserProject", &query, variables)
+		return &query.Owner.Project, err
+	} else if o.Type == OrgOwner {
+		variables["login"] = githubv4.String(o.Login)
+		var query orgOwner
+		err := c.doQueryWithContext(ctx, "OrgProject", &query, variables)
+		return &query.Owner.Project, err
+	} else if o.Type == ViewerOwner {
+		var query viewerOwner
+		err := c.doQueryWithContext(ctx, "ViewerProject", &query, variables)
+		return &query.Owner.Project, err
+	}
+	return nil, errors.New("unknown owner type")
+}
+
+// ErrNotAProjectNode is returned by ProjectByID when id resolves to a node
+// that exists but isn't a ProjectV2. GraphQL doesn't surface this as an
+// error on its own: an inline fragment that doesn't match the node's actual
+// type simply comes back empty, so ProjectByID has to detect the empty
+// result and turn it into something callers can act on.
+var ErrNotAProjectNode = errors.New("not a ProjectV2 node")
+
+// projectByID queries a single ProjectV2 node directly by its GraphQL node
+// ID, bypassing the owner+number resolution NewProject relies on.
+type projectByID struct {
+	Node struct {
+		Project Project `graphql:"... on ProjectV2"`
+	} `graphql:"node(id: $id)"`
+}
+
+// ProjectByID looks up a project directly by its node ID. It returns
+// ErrNotAProjectNode if id resolves to a node of some other type, and
+// whatever GraphQL error it received (typically NOT_FOUND) if id doesn't
+// resolve to any node at all.
+func (c *Client) ProjectByID(id string, fields bool) (*Project, error) {
+	variables := map[string]interface{}{
+		"id":          githubv4.ID(id),
+		"firstItems":  githubv4.Int(0),
+		"afterItems":  (*githubv4.String)(nil),
+		"firstFields": githubv4.Int(0),
+		"afterFields": (*githubv4.String)(nil),
+	}
+	if fields {
+		variables["firstFields"] = githubv4.Int(LimitMax)
+	}
+
+	var query projectByID
+	if err := c.doQuery("ProjectByID", &query, variables); err != nil {
+		return nil, err
+	}
+	if query.Node.Project.ID == "" {
+		return nil, ErrNotAProjectNode
+	}
+	return &query.Node.Project, nil
+}
+
 // Projects returns all the projects for an Owner. If the OwnerType is VIEWER, no login is required.
 // If limit is 0, the default limit is used.
 func (c *Client) Projects(login string, t OwnerType, limit int, fields bool) (Projects, error) {
@@ -1369,6 +1931,336 @@ func (c *Client) Projects(login string, t OwnerType, limit int, fields bool) (Pr
 	}
 }
 
+// TemplateProject is a row in an organization's template gallery, as
+// returned by TemplateProjects. It carries only the fields an audit needs
+// rather than reusing Project's much larger item/field selection.
+type TemplateProject struct {
+	Number    int32
+	Title     string
+	URL       string
+	Public    bool
+	UpdatedAt time.Time
+	Items     struct {
+		TotalCount int
+	} `graphql:"items(first: 0)"`
+}
+
+// TemplateProjects is the paginated result of a TemplateProjects call.
+type TemplateProjects struct {
+	Nodes      []TemplateProject
+	TotalCount int
+}
+
+// orgProjectTemplates queries $first projects of an organization's template
+// gallery.
+type orgProjectTemplates struct {
+	Owner struct {
+		Projects struct {
+			TotalCount int
+			PageInfo   PageInfo
+			Nodes      []TemplateProject
+		} `graphql:"projectV2Templates(first: $first, after: $after)"`
+		Login string
+	} `graphql:"organization(login: $login)"`
+}
+
+// TemplateProjects returns every project in login's template gallery, fully
+// paginating through the result.
+func (c *Client) TemplateProjects(login string) (TemplateProjects, error) {
+	projects := TemplateProjects{
+		Nodes: make([]TemplateProject, 0),
+	}
+	cursor := (*githubv4.String)(nil)
+
+	variables := map[string]interface{}{
+		"login": githubv4.String(login),
+		"first": githubv4.Int(LimitMax),
+		"after": cursor,
+	}
+
+	for {
+		var query orgProjectTemplates
+		if err := c.doQuery("OrgProjectTemplates", &query, variables); err != nil {
+			return projects, err
+		}
+		projects.Nodes = append(projects.Nodes, query.Owner.Projects.Nodes...)
+		projects.TotalCount = query.Owner.Projects.TotalCount
+
+		if !query.Owner.Projects.PageInfo.HasNextPage {
+			return projects, nil
+		}
+		cursor = &query.Owner.Projects.PageInfo.EndCursor
+		variables["after"] = cursor
+	}
+}
+
+// DerivedProject is a project that was created from another project via
+// "use as template", as returned by DerivedProjects.
+type DerivedProject struct {
+	Number int32
+	Title  string
+	URL    string
+	Owner  struct {
+		TypeName string `graphql:"__typename"`
+		User     struct {
+			Login string
+		} `graphql:"... on User"`
+		Organization struct {
+			Login string
+		} `graphql:"... on Organization"`
+	}
+}
+
+// OwnerLogin returns the login of whichever owner type the project belongs
+// to, mirroring Project.OwnerLogin.
+func (d DerivedProject) OwnerLogin() string {
+	if d.Owner.TypeName == "User" {
+		return d.Owner.User.Login
+	}
+	return d.Owner.Organization.Login
+}
+
+func (d DerivedProject) ExportData(_ []string) map[string]interface{} {
+	return map[string]interface{}{
+		"number": d.Number,
+		"title":  d.Title,
+		"url":    d.URL,
+		"owner":  d.OwnerLogin(),
+	}
+}
+
+// DerivedProjects is the paginated result of a DerivedProjects call.
+type DerivedProjects struct {
+	Nodes      []DerivedProject
+	TotalCount int
+}
+
+// projectDerivatives queries $first projects derived from a template
+// project.
+type projectDerivatives struct {
+	Owner struct {
+		ProjectV2 struct {
+			Derivatives struct {
+				TotalCount int
+				PageInfo   PageInfo
+				Nodes      []DerivedProject
+			} `graphql:"derivedProjects(first: $first, after: $after)"`
+		} `graphql:"projectV2(number: $number)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// DerivedProjects returns every project derived from the template project
+// identified by login/number, fully paginating through the result. It
+// returns a clear error if the host's GraphQL schema does not expose
+// derived projects at all (see TemplateCapabilities.Derivatives), rather
+// than letting the query fail with an opaque "Cannot query field" error.
+func (c *Client) DerivedProjects(login string, number int32) (DerivedProjects, error) {
+	caps, err := c.ProbeTemplateCapabilities()
+	if err != nil {
+		return DerivedProjects{}, err
+	}
+	if !caps.Derivatives {
+		return DerivedProjects{}, fmt.Errorf("this host's GraphQL schema does not expose derived projects")
+	}
+
+	derivatives := DerivedProjects{
+		Nodes: make([]DerivedProject, 0),
+	}
+	cursor := (*githubv4.String)(nil)
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(login),
+		"number": githubv4.Int(number),
+		"first":  githubv4.Int(LimitMax),
+		"after":  cursor,
+	}
+
+	for {
+		var query projectDerivatives
+		if err := c.doQuery("ProjectDerivatives", &query, variables); err != nil {
+			return derivatives, err
+		}
+		derivatives.Nodes = append(derivatives.Nodes, query.Owner.ProjectV2.Derivatives.Nodes...)
+		derivatives.TotalCount = query.Owner.ProjectV2.Derivatives.TotalCount
+
+		if !query.Owner.ProjectV2.Derivatives.PageInfo.HasNextPage {
+			return derivatives, nil
+		}
+		cursor = &query.Owner.ProjectV2.Derivatives.PageInfo.EndCursor
+		variables["after"] = cursor
+	}
+}
+
+// DerivedProjectsPage is a single page of derived projects, including the
+// PageInfo needed to resume the listing with --after.
+type DerivedProjectsPage struct {
+	Nodes      []DerivedProject
+	TotalCount int
+	PageInfo   PageInfo
+}
+
+// DerivedProjectsPage returns a single page of at most first projects
+// derived from the template project identified by login/number, starting
+// after the given cursor (pass "" to start from the beginning), instead of
+// fully paginating through the whole result the way DerivedProjects does.
+// This backs --limit/--after for resumable listing of large derivative
+// sets. first is clamped to [1, LimitMax].
+func (c *Client) DerivedProjectsPage(login string, number int32, first int, after string) (DerivedProjectsPage, error) {
+	caps, err := c.ProbeTemplateCapabilities()
+	if err != nil {
+		return DerivedProjectsPage{}, err
+	}
+	if !caps.Derivatives {
+		return DerivedProjectsPage{}, fmt.Errorf("this host's GraphQL schema does not expose derived projects")
+	}
+
+	if first < 1 {
+		first = 1
+	} else if first > LimitMax {
+		first = LimitMax
+	}
+
+	var cursor *githubv4.String
+	if after != "" {
+		c := githubv4.String(after)
+		cursor = &c
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(login),
+		"number": githubv4.Int(number),
+		"first":  githubv4.Int(first),
+		"after":  cursor,
+	}
+
+	var query projectDerivatives
+	if err := c.doQuery("ProjectDerivatives", &query, variables); err != nil {
+		return DerivedProjectsPage{}, err
+	}
+
+	return DerivedProjectsPage{
+		Nodes:      query.Owner.ProjectV2.Derivatives.Nodes,
+		TotalCount: query.Owner.ProjectV2.Derivatives.TotalCount,
+		PageInfo:   query.Owner.ProjectV2.Derivatives.PageInfo,
+	}, nil
+}
+
+// LinkedProject is a project linked to a repository, as returned by
+// LinkedProjects.
+type LinkedProject struct {
+	ID     string
+	Number int32
+	Title  string
+	URL    string
+	Owner  struct {
+		TypeName string `graphql:"__typename"`
+		User     struct {
+			Login string
+		} `graphql:"... on User"`
+		Organization struct {
+			Login string
+		} `graphql:"... on Organization"`
+	}
+}
+
+// OwnerLogin returns the login of whichever owner type the project belongs
+// to, mirroring Project.OwnerLogin.
+func (p LinkedProject) OwnerLogin() string {
+	if p.Owner.TypeName == "User" {
+		return p.Owner.User.Login
+	}
+	return p.Owner.Organization.Login
+}
+
+func (p LinkedProject) ExportData(_ []string) map[string]interface{} {
+	return map[string]interface{}{
+		"number": p.Number,
+		"title":  p.Title,
+		"url":    p.URL,
+		"owner":  p.OwnerLogin(),
+	}
+}
+
+// repoLinkedProjects queries the $first projects linked to a repository.
+type repoLinkedProjects struct {
+	Repository struct {
+		ProjectsV2 struct {
+			TotalCount int
+			PageInfo   PageInfo
+			Nodes      []LinkedProject
+		} `graphql:"projectsV2(first: $first, after: $after)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// LinkedProjects returns every project linked to the repository identified
+// by owner/name, fully paginating through the result.
+func (c *Client) LinkedProjects(owner, name string) ([]LinkedProject, error) {
+	projects := make([]LinkedProject, 0)
+	cursor := (*githubv4.String)(nil)
+
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+		"first": githubv4.Int(LimitMax),
+		"after": cursor,
+	}
+
+	for {
+		var query repoLinkedProjects
+		if err := c.doQuery("RepoLinkedProjects", &query, variables); err != nil {
+			return nil, err
+		}
+		projects = append(projects, query.Repository.ProjectsV2.Nodes...)
+
+		if !query.Repository.ProjectsV2.PageInfo.HasNextPage {
+			return projects, nil
+		}
+		cursor = &query.Repository.ProjectsV2.PageInfo.EndCursor
+		variables["after"] = cursor
+	}
+}
+
+// projectSourceTemplate queries the template a project was created from via
+// "use as template", the reverse of projectDerivatives.
+type projectSourceTemplate struct {
+	Owner struct {
+		ProjectV2 struct {
+			SourceTemplate DerivedProject `graphql:"sourceTemplate"`
+		} `graphql:"projectV2(number: $number)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// SourceTemplate returns the template project that login/number's project
+// was created from via "use as template", or nil if it wasn't created from
+// a template at all. It returns a clear error if the host's GraphQL schema
+// does not expose a project's source template (see
+// TemplateCapabilities.SourceTemplate), rather than letting the query fail
+// with an opaque "Cannot query field" error.
+func (c *Client) SourceTemplate(login string, number int32) (*DerivedProject, error) {
+	caps, err := c.ProbeTemplateCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	if !caps.SourceTemplate {
+		return nil, fmt.Errorf("this host's GraphQL schema does not expose a project's source template")
+	}
+
+	var query projectSourceTemplate
+	variables := map[string]interface{}{
+		"login":  githubv4.String(login),
+		"number": githubv4.Int(number),
+	}
+	if err := c.doQuery("ProjectSourceTemplate", &query, variables); err != nil {
+		return nil, err
+	}
+
+	source := query.Owner.ProjectV2.SourceTemplate
+	if source.Number == 0 && source.Title == "" {
+		return nil, nil
+	}
+	return &source, nil
+}
+
 type linkProjectToRepoMutation struct {
 	LinkProjectV2ToRepository struct {
 		ClientMutationId string `graphql:"clientMutationId"`
@@ -365,6 +365,53 @@ func TestNewOwner_nonTTY(t *testing.T) {
 
 }
 
+func TestOwnerIDAndType_AssumedOrgOwner(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		JSON(map[string]interface{}{
+			"query":     "query AssumedOrgOwner.*",
+			"variables": map[string]interface{}{"login": "cli"},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"id": "org ID"},
+			},
+		})
+
+	client := NewTestClient()
+	client.SetAssumedOwnerType(OrgOwner)
+
+	id, ownerType, err := client.OwnerIDAndType("cli")
+	assert.NoError(t, err)
+	assert.Equal(t, "org ID", id)
+	assert.Equal(t, OrgOwner, ownerType)
+}
+
+func TestOwnerIDAndType_AssumedOwnerTypeMismatch(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		JSON(map[string]interface{}{
+			"query":     "query AssumedOrgOwner.*",
+			"variables": map[string]interface{}{"login": "monalisa"},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data":   map[string]interface{}{"organization": nil},
+			"errors": []interface{}{map[string]interface{}{"type": "NOT_FOUND", "path": []string{"organization"}}},
+		})
+
+	client := NewTestClient()
+	client.SetAssumedOwnerType(OrgOwner)
+
+	_, _, err := client.OwnerIDAndType("monalisa")
+	assert.ErrorContains(t, err, `--assume-owner-type org: "monalisa" is not an organization`)
+}
+
 func TestProjectItems_FieldTitle(t *testing.T) {
 	defer gock.Off()
 	gock.Observe(gock.DumpRequest)
@@ -0,0 +1,134 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+)
+
+// linkedMatch records the outcome of templating one project matched by
+// --all-linked.
+type linkedMatch struct {
+	Project queries.Project `json:"project"`
+	Error   string          `json:"error,omitempty"`
+	Code    errorCode       `json:"code,omitempty"`
+}
+
+// linkedProjectAsProject adapts a queries.LinkedProject to a queries.Project
+// carrying just the fields the two share, so a failed entry's Project field
+// can still report a number, title, URL, and owner without a mutation
+// response to read them back from.
+func linkedProjectAsProject(lp queries.LinkedProject) queries.Project {
+	return queries.Project{
+		ID:     lp.ID,
+		Number: lp.Number,
+		Title:  lp.Title,
+		URL:    lp.URL,
+		Owner:  lp.Owner,
+	}
+}
+
+// runAllLinked templates every project linked to the repository in
+// config.opts.repo, enumerated via the repository's projectsV2 connection,
+// for standardizing all of a repo's associated projects in one command. A
+// repo's linked projects can span more than one owner, the same way
+// --search's matches can, so --all-linked always requires confirmation (or
+// --yes) regardless of --confirm-threshold.
+func runAllLinked(config templateConfig) error {
+	owner, name, ok := strings.Cut(config.opts.repo, "/")
+	if !ok {
+		return fmt.Errorf("expected the \"OWNER/REPO\" format, got %q", config.opts.repo)
+	}
+
+	linked, err := config.client.LinkedProjects(owner, name)
+	if err != nil {
+		return fmt.Errorf("could not list projects linked to %s: %w", config.opts.repo, err)
+	}
+
+	if len(linked) == 0 {
+		return printEmptyProjectList(config)
+	}
+
+	if config.io.IsStderrTTY() && !config.opts.quiet {
+		fmt.Fprintf(config.io.ErrOut, "%s has %d linked projects\n", config.opts.repo, len(linked))
+	}
+
+	if err := confirmAllLinkedOperation(config, len(linked)); err != nil {
+		return err
+	}
+
+	summary := batchSummary{}
+	results := make([]linkedMatch, 0, len(linked))
+
+	for _, lp := range linked {
+		entryConfig := config
+		entryConfig.opts.projectID = lp.ID
+
+		mutated, _, noop, err := applyMutation(entryConfig, nil)
+		if err != nil {
+			summary.Failed++
+			results = append(results, linkedMatch{Project: linkedProjectAsProject(lp), Error: err.Error(), Code: classifyError(err)})
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case noop:
+			summary.Noop++
+		case config.opts.undo:
+			summary.Unmarked++
+		default:
+			summary.Marked++
+		}
+		results = append(results, linkedMatch{Project: mutated})
+	}
+
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+
+	var output interface{} = results
+	if config.opts.groupByOwner {
+		grouped := groupLinkedMatchesByOwner(results)
+		if err := printGroupedLinkedMatches(config, grouped); err != nil {
+			return err
+		}
+		output = grouped
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, output); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, output)
+}
+
+// confirmAllLinkedOperation unconditionally requires confirmation (or --yes
+// when the command cannot prompt) before templating count projects linked
+// to --repo.
+func confirmAllLinkedOperation(config templateConfig, count int) error {
+	if err := checkMaxAffected(config, count); err != nil {
+		return err
+	}
+
+	if config.opts.yes {
+		return nil
+	}
+
+	if !config.io.CanPrompt() {
+		return cmdutil.FlagErrorf("--yes is required when templating projects linked to --repo %s (matched %d)", config.opts.repo, count)
+	}
+
+	confirmed, err := config.prompter.Confirm(fmt.Sprintf("This will %s %d projects linked to %s. Continue?", verbForUndo(config.opts.undo), count, config.opts.repo), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cmdutil.CancelError
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRunAllLinked(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query RepoLinkedProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"projectsV2": map[string]interface{}{
+						"nodes": []interface{}{
+							map[string]interface{}{"id": "project ID", "number": 1},
+						},
+						"pageInfo": map[string]interface{}{"hasNextPage": false},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID", "number": 1},
+				},
+			},
+		})
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:   templateOpts{repo: "github/showcase", allLinked: true, yes: true},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAllLinked(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "github/showcase has 1 linked projects")
+	assert.Contains(t, stderr.String(), "1 processed, 1 marked")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestRunAllLinked_NoLinkedProjects(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query RepoLinkedProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"projectsV2": map[string]interface{}{
+						"nodes":    []interface{}{},
+						"pageInfo": map[string]interface{}{"hasNextPage": false},
+					},
+				},
+			},
+		})
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:   templateOpts{repo: "github/showcase", allLinked: true, yes: true},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAllLinked(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "No projects found.")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestRunAllLinked_RequiresYes(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query RepoLinkedProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"projectsV2": map[string]interface{}{
+						"nodes": []interface{}{
+							map[string]interface{}{"id": "project ID", "number": 1},
+						},
+						"pageInfo": map[string]interface{}{"hasNextPage": false},
+					},
+				},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{repo: "github/showcase", allLinked: true},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAllLinked(config)
+	assert.ErrorContains(t, err, "--yes is required")
+}
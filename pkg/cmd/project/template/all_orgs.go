@@ -0,0 +1,172 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// errProjectNotMatched signals that none of an organization's projects
+// matched the requested number/title, so that run is skipped rather than
+// counted as a failure.
+var errProjectNotMatched = errors.New("no matching project")
+
+// orgResult records the outcome of an --all-orgs run for one organization.
+type orgResult struct {
+	Owner   string          `json:"owner"`
+	Project queries.Project `json:"project,omitempty"`
+	Skipped bool            `json:"skipped"`
+	Error   string          `json:"error,omitempty"`
+	Code    errorCode       `json:"code,omitempty"`
+}
+
+// runAllOrgs applies the template operation to a project matching
+// config.opts.number or config.opts.title in every organization the viewer
+// belongs to. It is subject to the --confirm-threshold guard, since a
+// single typo'd title can touch many orgs at once.
+func runAllOrgs(config templateConfig) error {
+	orgs, err := config.client.ViewerOrgs()
+	if err != nil {
+		return err
+	}
+
+	return runOrgsBatch(config, orgs, fmt.Sprintf("a matching project across all %d organizations you belong to", len(orgs)))
+}
+
+// runMyOrgs applies the template operation to a project matching
+// config.opts.number or config.opts.title in every organization the viewer
+// is a member of, including orgs where the viewer cannot write projects
+// (unlike --all-orgs, which only considers orgs the viewer can create
+// projects in). A write operation that reaches an org the viewer lacks
+// permission for fails just that one org, via runOrgsBatch's existing
+// per-org error handling, rather than aborting the whole run.
+func runMyOrgs(config templateConfig) error {
+	orgs, err := config.client.ViewerMemberOrgs()
+	if err != nil {
+		return err
+	}
+
+	return runOrgsBatch(config, orgs, fmt.Sprintf("a matching project across all %d organizations you're a member of", len(orgs)))
+}
+
+// runOrgsBatch applies the template operation to a project matching
+// config.opts.number or config.opts.title in each of orgs, subject to the
+// --confirm-threshold guard described by subject. It backs --all-orgs,
+// --enterprise, and --my-orgs, which differ only in how orgs is gathered.
+func runOrgsBatch(config templateConfig, orgs []queries.Owner, subject string) error {
+	preview := func() ([]queries.Project, error) {
+		matched := make([]queries.Project, 0, len(orgs))
+		for _, org := range orgs {
+			owner := org
+			project, err := findMatchingProject(config, &owner)
+			if err != nil {
+				if errors.Is(err, errProjectNotMatched) {
+					continue
+				}
+				return nil, err
+			}
+			matched = append(matched, project)
+		}
+		return matched, nil
+	}
+
+	if err := confirmBulkOperation(config, len(orgs), subject, preview); err != nil {
+		return err
+	}
+
+	summary := batchSummary{}
+	results := make([]orgResult, 0, len(orgs))
+
+	for _, org := range orgs {
+		owner := org
+		result := orgResult{Owner: org.Login}
+
+		project, err := findMatchingProject(config, &owner)
+		if err != nil {
+			if errors.Is(err, errProjectNotMatched) {
+				summary.Skipped++
+				result.Skipped = true
+				results = append(results, result)
+				continue
+			}
+			summary.Failed++
+			result.Error = err.Error()
+			result.Code = classifyError(err)
+			results = append(results, result)
+			continue
+		}
+
+		entryConfig := config
+		entryConfig.opts.projectID = project.ID
+		mutated, _, noop, err := applyMutation(entryConfig, &owner)
+		if err != nil {
+			summary.Failed++
+			result.Error = err.Error()
+			result.Code = classifyError(err)
+			results = append(results, result)
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case noop:
+			summary.Noop++
+		case config.opts.undo:
+			summary.Unmarked++
+		default:
+			summary.Marked++
+		}
+		result.Project = mutated
+		results = append(results, result)
+	}
+
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+
+	var output interface{} = results
+	if config.opts.groupByOwner {
+		grouped := groupOrgResultsByOwner(results)
+		if err := printGroupedOrgResults(config, grouped); err != nil {
+			return err
+		}
+		output = grouped
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, output); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, output)
+}
+
+// findMatchingProject looks up owner's projects and returns the one matching
+// config.opts.number (if set) or config.opts.title, or errProjectNotMatched
+// if owner has no such project.
+func findMatchingProject(config templateConfig, owner *queries.Owner) (queries.Project, error) {
+	projects, err := config.client.Projects(owner.Login, owner.Type, 0, false)
+	if err != nil {
+		return queries.Project{}, err
+	}
+
+	for _, p := range projects.Nodes {
+		if config.opts.number != 0 && p.Number == config.opts.number {
+			return p, nil
+		}
+		if config.opts.number == 0 && config.opts.title != "" && p.Title == config.opts.title {
+			return p, nil
+		}
+	}
+
+	return queries.Project{}, errProjectNotMatched
+}
+
+func verbForUndo(undo bool) string {
+	if undo {
+		return "unmark as a template"
+	}
+	return "mark as a template"
+}
@@ -0,0 +1,50 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// annotationsEnabled reports whether failures and warnings should also be
+// emitted as GitHub Actions workflow command annotations, so they surface in
+// the Actions UI's annotations list instead of only in the raw log. It
+// auto-detects running inside Actions via GITHUB_ACTIONS=true, and can be
+// suppressed with --no-annotations for workflows that parse stderr
+// themselves and don't want the extra lines.
+func annotationsEnabled(config templateConfig) bool {
+	return !config.opts.noAnnotations && os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// printErrorAnnotation emits a GitHub Actions "::error::" workflow command
+// for a fatal error, in addition to however the caller already reports it,
+// so it also surfaces as an annotation in the Actions UI.
+func printErrorAnnotation(config templateConfig, err error) error {
+	if !annotationsEnabled(config) {
+		return nil
+	}
+	_, werr := fmt.Fprintf(config.io.ErrOut, "::error::%s\n", escapeAnnotationMessage(err.Error()))
+	return werr
+}
+
+// printWarningAnnotation emits a GitHub Actions "::warning::" workflow
+// command for a non-fatal warning already reported via the usual
+// warning-icon message, so it also surfaces as an annotation.
+func printWarningAnnotation(config templateConfig, message string) error {
+	if !annotationsEnabled(config) {
+		return nil
+	}
+	_, werr := fmt.Fprintf(config.io.ErrOut, "::warning::%s\n", escapeAnnotationMessage(message))
+	return werr
+}
+
+// escapeAnnotationMessage escapes the characters that Actions workflow
+// commands treat specially (percent, carriage return, newline), so a
+// message containing one still renders as a single well-formed annotation
+// instead of being cut short or split across lines.
+func escapeAnnotationMessage(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
@@ -0,0 +1,64 @@
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotationsEnabled(t *testing.T) {
+	tests := []struct {
+		name          string
+		githubActions string
+		noAnnotations bool
+		want          bool
+	}{
+		{name: "detected", githubActions: "true", want: true},
+		{name: "not in Actions", githubActions: "", want: false},
+		{name: "opted out", githubActions: "true", noAnnotations: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_ACTIONS", tt.githubActions)
+			config := templateConfig{opts: templateOpts{noAnnotations: tt.noAnnotations}}
+			assert.Equal(t, tt.want, annotationsEnabled(config))
+		})
+	}
+}
+
+func TestPrintErrorAnnotation(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	ios, _, _, stderr := iostreams.Test()
+	config := templateConfig{io: ios}
+
+	err := printErrorAnnotation(config, errors.New("could not mark project 1 as a template"))
+	assert.NoError(t, err)
+	assert.Equal(t, "::error::could not mark project 1 as a template\n", stderr.String())
+}
+
+func TestPrintWarningAnnotation(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	ios, _, _, stderr := iostreams.Test()
+	config := templateConfig{io: ios}
+
+	err := printWarningAnnotation(config, "--post-hook failed: exit status 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "::warning::--post-hook failed: exit status 1\n", stderr.String())
+}
+
+func TestPrintWarningAnnotation_NotInActions(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	ios, _, _, stderr := iostreams.Test()
+	config := templateConfig{io: ios}
+
+	err := printWarningAnnotation(config, "--post-hook failed: exit status 1")
+	assert.NoError(t, err)
+	assert.Empty(t, stderr.String())
+}
+
+func TestEscapeAnnotationMessage(t *testing.T) {
+	assert.Equal(t, "100%25 done%0Anext line%0Dreturn", escapeAnnotationMessage("100% done\nnext line\rreturn"))
+}
@@ -0,0 +1,66 @@
+package template
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// atomicWriteFile writes to path by first writing to a temp file in the same
+// directory and renaming it into place on success, so a failure partway
+// through write (e.g. a full disk) never leaves path holding a truncated,
+// half-written file for a downstream tool to pick up. The temp file is
+// removed if anything goes wrong. Used by every feature that writes a
+// complete document to a file in one shot: --output, --dump-state, and
+// `template audit`'s CSV. When gzipOutput is true, write's output is
+// gzip-compressed as it's written, and ".gz" is appended to path if it
+// isn't already present, so the file on disk always matches its name.
+func atomicWriteFile(path string, gzipOutput bool, write func(io.Writer) error) error {
+	if gzipOutput && !strings.HasSuffix(path, ".gz") {
+		path += ".gz"
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not write %q: %w", path, err)
+	}
+
+	writeErr := func() error {
+		var out io.Writer = tmp
+		var gz *gzip.Writer
+		if gzipOutput {
+			gz = gzip.NewWriter(tmp)
+			out = gz
+		}
+
+		if err := write(out); err != nil {
+			return err
+		}
+		if gz != nil {
+			return gz.Close()
+		}
+		return nil
+	}()
+
+	if writeErr != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not write %q: %w", path, writeErr)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not write %q: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not write %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package template
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicWriteFile_Success(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+
+	err := atomicWriteFile(path, false, func(w io.Writer) error {
+		_, err := io.WriteString(w, `{"ok":true}`)
+		return err
+	})
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(data))
+}
+
+// TestAtomicWriteFile_Gzip asserts that gzipOutput appends ".gz" to path and
+// writes a valid gzip stream that decompresses back to the original content.
+func TestAtomicWriteFile_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+
+	err := atomicWriteFile(path, true, func(w io.Writer) error {
+		_, err := io.WriteString(w, `{"ok":true}`)
+		return err
+	})
+	assert.NoError(t, err)
+
+	_, statErr := os.Stat(path)
+	assert.True(t, os.IsNotExist(statErr), "plain path must not exist when gzipOutput is set")
+
+	f, err := os.Open(path + ".gz")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	defer gz.Close()
+
+	decompressed, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(decompressed))
+}
+
+// TestAtomicWriteFile_GzipAlreadyHasSuffix asserts that a path already
+// ending in ".gz" isn't doubled up.
+func TestAtomicWriteFile_GzipAlreadyHasSuffix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json.gz")
+
+	err := atomicWriteFile(path, true, func(w io.Writer) error {
+		_, err := io.WriteString(w, "hello")
+		return err
+	})
+	assert.NoError(t, err)
+
+	_, statErr := os.Stat(path)
+	assert.NoError(t, statErr)
+}
+
+// TestAtomicWriteFile_WriteFailureLeavesNoPartialFile simulates a write
+// failing partway through (e.g. a full disk) and asserts that the target
+// path is never created and no temp file is left behind, so a downstream
+// tool never sees a truncated, half-written document.
+func TestAtomicWriteFile_WriteFailureLeavesNoPartialFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+
+	err := atomicWriteFile(path, false, func(w io.Writer) error {
+		if _, err := io.WriteString(w, `{"incomplete":`); err != nil {
+			return err
+		}
+		return errors.New("disk full")
+	})
+	assert.ErrorContains(t, err, "disk full")
+	assert.ErrorContains(t, err, path)
+
+	_, statErr := os.Stat(path)
+	assert.True(t, os.IsNotExist(statErr), "target file must not exist after a failed write")
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "no temp file should be left behind after a failed write")
+}
+
+func TestAtomicWriteFile_CreateTempFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist", "out.json")
+
+	err := atomicWriteFile(path, false, func(w io.Writer) error {
+		t.Fatal("write should not be called when the temp file could not be created")
+		return nil
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,497 @@
+package template
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/google/shlex"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestNewCmdAudit(t *testing.T) {
+	tests := []struct {
+		name        string
+		cli         string
+		wants       auditOpts
+		wantsErr    bool
+		wantsErrMsg string
+	}{
+		{
+			name:        "no owner",
+			cli:         "",
+			wantsErr:    true,
+			wantsErrMsg: "--owner is required",
+		},
+		{
+			name: "owner",
+			cli:  "--owner github",
+			wants: auditOpts{
+				owner: "github",
+			},
+		},
+		{
+			name: "output",
+			cli:  "--owner github --output templates.csv",
+			wants: auditOpts{
+				owner:  "github",
+				output: "templates.csv",
+			},
+		},
+		{
+			name: "columns",
+			cli:  "--owner github --columns number,title,public",
+			wants: auditOpts{
+				owner:   "github",
+				columns: "number,title,public",
+			},
+		},
+		{
+			name:        "unknown column",
+			cli:         "--owner github --columns number,color",
+			wantsErr:    true,
+			wantsErrMsg: `unknown column "color": valid columns are number, title, url, public, updatedAt, itemCount`,
+		},
+		{
+			name: "dump-state",
+			cli:  "--owner github --dump-state",
+			wants: auditOpts{
+				owner:     "github",
+				dumpState: true,
+			},
+		},
+		{
+			name:        "dump-state with columns",
+			cli:         "--owner github --dump-state --columns number",
+			wantsErr:    true,
+			wantsErrMsg: "--dump-state cannot be combined with --columns",
+		},
+		{
+			name: "indent",
+			cli:  "--owner github --dump-state --indent 4",
+			wants: auditOpts{
+				owner:     "github",
+				dumpState: true,
+				indent:    4,
+			},
+		},
+		{
+			name:        "indent out of range",
+			cli:         "--owner github --indent 9",
+			wantsErr:    true,
+			wantsErrMsg: "--indent must be between 0 and 8",
+		},
+		{
+			name: "gzip",
+			cli:  "--owner github --output templates.csv --gzip",
+			wants: auditOpts{
+				owner:  "github",
+				output: "templates.csv",
+				gzip:   true,
+			},
+		},
+		{
+			name:        "gzip without output",
+			cli:         "--owner github --gzip",
+			wantsErr:    true,
+			wantsErrMsg: "--gzip requires --output",
+		},
+	}
+
+	t.Setenv("GH_TOKEN", "auth-token")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, _, _, _ := iostreams.Test()
+			f := &cmdutil.Factory{
+				IOStreams: ios,
+			}
+
+			argv, err := shlex.Split(tt.cli)
+			assert.NoError(t, err)
+
+			var gotOpts auditOpts
+			cmd := newCmdAudit(f, func(config auditConfig) error {
+				gotOpts = config.opts
+				return nil
+			})
+
+			cmd.SetArgs(argv)
+			_, err = cmd.ExecuteC()
+			if tt.wantsErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantsErrMsg, err.Error())
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wants.owner, gotOpts.owner)
+			assert.Equal(t, tt.wants.output, gotOpts.output)
+			assert.Equal(t, tt.wants.columns, gotOpts.columns)
+			assert.Equal(t, tt.wants.dumpState, gotOpts.dumpState)
+			assert.Equal(t, tt.wants.gzip, gotOpts.gzip)
+			if tt.wants.indent != 0 {
+				assert.Equal(t, tt.wants.indent, gotOpts.indent)
+			} else if !tt.wantsErr {
+				assert.Equal(t, 2, gotOpts.indent)
+			}
+		})
+	}
+}
+
+func TestParseAuditColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr string
+	}{
+		{name: "default", raw: "", want: auditColumns},
+		{name: "subset and reorder", raw: "public,number", want: []string{"public", "number"}},
+		{name: "whitespace", raw: " number , title ", want: []string{"number", "title"}},
+		{
+			name:    "unknown",
+			raw:     "number,color",
+			wantErr: `unknown column "color": valid columns are number, title, url, public, updatedAt, itemCount`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAuditColumns(tt.raw)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunAudit(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 2,
+						"pageInfo": map[string]interface{}{
+							"hasNextPage": false,
+							"endCursor":   "",
+						},
+						"nodes": []interface{}{
+							map[string]interface{}{
+								"number":    1,
+								"title":     `Roadmap, "2024"`,
+								"url":       "https://github.com/orgs/github/projects/1",
+								"public":    true,
+								"updatedAt": "2024-01-02T15:04:05Z",
+								"items": map[string]interface{}{
+									"totalCount": 3,
+								},
+							},
+							map[string]interface{}{
+								"number":    2,
+								"title":     "Backlog",
+								"url":       "https://github.com/orgs/github/projects/2",
+								"public":    false,
+								"updatedAt": "2024-02-03T10:00:00Z",
+								"items": map[string]interface{}{
+									"totalCount": 0,
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := auditConfig{
+		opts:   auditOpts{owner: "github"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAudit(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "number,title,url,public,updatedAt,itemCount\n"+
+		"1,\"Roadmap, \"\"2024\"\"\",https://github.com/orgs/github/projects/1,true,2024-01-02T15:04:05Z,3\n"+
+		"2,Backlog,https://github.com/orgs/github/projects/2,false,2024-02-03T10:00:00Z,0\n",
+		stdout.String())
+}
+
+func TestRunAudit_Columns(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo": map[string]interface{}{
+							"hasNextPage": false,
+							"endCursor":   "",
+						},
+						"nodes": []interface{}{
+							map[string]interface{}{
+								"number":    1,
+								"title":     "Roadmap",
+								"url":       "https://github.com/orgs/github/projects/1",
+								"public":    true,
+								"updatedAt": "2024-01-02T15:04:05Z",
+								"items": map[string]interface{}{
+									"totalCount": 3,
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := auditConfig{
+		opts:   auditOpts{owner: "github", columns: "public,number"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAudit(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "public,number\ntrue,1\n", stdout.String())
+}
+
+func TestRunDumpState(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectsV2": map[string]interface{}{
+						"totalCount": 2,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"title": "Roadmap", "id": "roadmap ID", "number": 1},
+							map[string]interface{}{"title": "Backlog", "id": "backlog ID", "number": 2},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes": []interface{}{
+							map[string]interface{}{
+								"number":    1,
+								"title":     "Roadmap",
+								"url":       "https://github.com/orgs/github/projects/1",
+								"public":    true,
+								"updatedAt": "2024-01-02T15:04:05Z",
+								"items":     map[string]interface{}{"totalCount": 3},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	fixedNow := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	config := auditConfig{
+		opts:   auditOpts{owner: "github", dumpState: true, now: func() time.Time { return fixedNow }},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAudit(config)
+	assert.NoError(t, err)
+	assert.JSONEq(t,
+		`{"owner":"github","timestamp":"2024-03-04T05:06:07Z","projects":{"1":true,"2":false}}`,
+		stdout.String())
+}
+
+func TestRunDumpState_Indent(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectsV2": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"title": "Roadmap", "id": "roadmap ID", "number": 1},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login":               "github",
+					"projectV2Templates": map[string]interface{}{"totalCount": 0, "pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": ""}, "nodes": []interface{}{}},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	fixedNow := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	config := auditConfig{
+		opts:   auditOpts{owner: "github", dumpState: true, indent: 4, now: func() time.Time { return fixedNow }},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAudit(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "\n    \"owner\"")
+}
+
+func TestRunAudit_Output(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 0,
+						"pageInfo": map[string]interface{}{
+							"hasNextPage": false,
+							"endCursor":   "",
+						},
+						"nodes": []interface{}{},
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/templates.csv"
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := auditConfig{
+		opts:   auditOpts{owner: "github", output: path},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAudit(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "", stdout.String())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "number,title,url,public,updatedAt,itemCount\n", string(data))
+}
+
+// TestRunAudit_OutputGzip asserts that --gzip appends ".gz" to --output's
+// path and writes a valid gzip stream that decompresses to the same CSV
+// --output would otherwise have written.
+func TestRunAudit_OutputGzip(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 0,
+						"pageInfo": map[string]interface{}{
+							"hasNextPage": false,
+							"endCursor":   "",
+						},
+						"nodes": []interface{}{},
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/templates.csv"
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := auditConfig{
+		opts:   auditOpts{owner: "github", output: path, gzip: true},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runAudit(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "", stdout.String())
+
+	_, statErr := os.Stat(path)
+	assert.True(t, os.IsNotExist(statErr), "plain path must not exist when --gzip is set")
+
+	f, err := os.Open(path + ".gz")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	defer gz.Close()
+
+	decompressed, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, "number,title,url,public,updatedAt,itemCount\n", string(decompressed))
+}
@@ -0,0 +1,83 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// runByID marks or unmarks the single project identified by config.opts.id,
+// a GraphQL node ID, skipping the owner+number resolution the rest of this
+// command relies on. This is for callers that already have the ID on hand,
+// e.g. from a prior --output JSON dump, and want to skip resolving it again.
+// --verify is not supported on this path (enforced at flag-parsing time),
+// since verifying re-reads the project by owner and number.
+func runByID(config templateConfig) error {
+	start := time.Now()
+
+	project, err := config.client.ProjectByID(config.opts.id, false)
+	if err != nil {
+		return classifyProjectIDError(config.opts.id, err)
+	}
+	config.opts.projectID = project.ID
+
+	mutated, retries, noop, err := applyMutation(config, nil)
+	if err != nil {
+		return err
+	}
+
+	summary := batchSummary{Processed: 1}
+	switch {
+	case noop:
+		summary.Noop = 1
+	case config.opts.undo:
+		summary.Unmarked = 1
+	default:
+		summary.Marked = 1
+	}
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+	if err := writeMetricsFile(config.opts.metricsFile, summary, retries, time.Since(start)); err != nil {
+		return err
+	}
+
+	exported := mutated
+	if config.opts.undo {
+		exported = *project
+	}
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, exported); err != nil {
+			return err
+		}
+	} else if err := printResults(config, mutated); err != nil {
+		return err
+	}
+	if err := writeOutputFile(config, exported); err != nil {
+		return err
+	}
+
+	if config.opts.linkRepo != "" {
+		if linkErr := linkProjectToRepo(config, mutated); linkErr != nil {
+			return fmt.Errorf("marked project %d as a template, but failed to link it to %s: %w", mutated.Number, config.opts.linkRepo, linkErr)
+		}
+		if err := printLinkResult(config, mutated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// classifyProjectIDError turns a ProjectByID failure into a message that
+// distinguishes a node that doesn't exist from one that exists but isn't a
+// ProjectV2, since the GraphQL response doesn't make that difference clear
+// on its own (see queries.ErrNotAProjectNode).
+func classifyProjectIDError(id string, err error) error {
+	if errors.Is(err, queries.ErrNotAProjectNode) {
+		return errors.New("the provided ID is not a ProjectV2 node")
+	}
+	return fmt.Errorf("could not find a project with ID %q: %w", id, err)
+}
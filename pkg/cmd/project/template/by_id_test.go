@@ -0,0 +1,118 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRunByID(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query ProjectByID.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"node": map[string]interface{}{
+					"id":     "an ID",
+					"number": 1,
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"an ID"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"id":     "an ID",
+						"number": 1,
+					},
+				},
+			},
+		})
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:   templateOpts{id: "an ID"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runByID(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "1 processed, 1 marked")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestRunByID_NotFound(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query ProjectByID.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"node": nil,
+			},
+			"errors": []interface{}{
+				map[string]interface{}{
+					"type":    "NOT_FOUND",
+					"message": "Could not resolve to a node with the global id of 'bogus'",
+					"path":    []string{"node"},
+				},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{id: "bogus"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runByID(config)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "ProjectV2 node")
+	assert.Contains(t, err.Error(), "could not find a project with ID")
+}
+
+func TestRunByID_WrongNodeType(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query ProjectByID.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				// the ID resolves to a real node (e.g. an Issue), so the
+				// inline fragment on ProjectV2 simply comes back empty.
+				"node": map[string]interface{}{},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{id: "an issue ID"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runByID(config)
+	assert.EqualError(t, err, "the provided ID is not a ProjectV2 node")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
@@ -0,0 +1,14 @@
+package template
+
+import "encoding/json"
+
+func runCapabilities(config templateConfig) error {
+	caps, err := config.client.ProbeTemplateCapabilities()
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(config.io.Out)
+	enc.SetIndent("", jsonIndent(config.opts.indent))
+	return enc.Encode(caps)
+}
@@ -0,0 +1,75 @@
+package template
+
+import (
+	"sync"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// entryOutcome is the result of processing one --from-file manifest entry.
+type entryOutcome struct {
+	mutated queries.Project
+	retries int
+	noop    bool
+	err     error
+}
+
+// runConcurrentEntries runs process for every entry in entries, honoring two
+// independent caps: at most concurrency entries run at once overall, and at
+// most ownerConcurrency entries for the same owner run at once. The owner
+// cap exists because --concurrency alone can still line up many entries for
+// a single owner at once if a manifest happens to target it repeatedly,
+// which risks tripping GitHub's per-account abuse-detection limits even
+// while overall throughput stays within --concurrency. Results are
+// returned in the same order as entries, regardless of completion order.
+// onEntry, if non-nil, is called once per entry as it finishes, from
+// whichever goroutine processed it; it must be safe to call concurrently.
+func runConcurrentEntries(concurrency, ownerConcurrency int, entries []manifestEntry, process func(manifestEntry) entryOutcome, onEntry func()) []entryOutcome {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if ownerConcurrency < 1 {
+		ownerConcurrency = 1
+	}
+
+	outcomes := make([]entryOutcome, len(entries))
+
+	global := make(chan struct{}, concurrency)
+
+	var ownerMu sync.Mutex
+	ownerSems := make(map[string]chan struct{})
+	ownerSem := func(owner string) chan struct{} {
+		ownerMu.Lock()
+		defer ownerMu.Unlock()
+		sem, ok := ownerSems[owner]
+		if !ok {
+			sem = make(chan struct{}, ownerConcurrency)
+			ownerSems[owner] = sem
+		}
+		return sem
+	}
+
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		i, entry := i, entry
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			global <- struct{}{}
+			defer func() { <-global }()
+
+			sem := ownerSem(entry.Owner)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			outcomes[i] = process(entry)
+			if onEntry != nil {
+				onEntry()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return outcomes
+}
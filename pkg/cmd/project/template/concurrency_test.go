@@ -0,0 +1,123 @@
+package template
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+// trackConcurrency returns a process func that records, for each owner, the
+// maximum number of calls in flight at once, plus a reader to retrieve it
+// once every call has returned.
+func trackConcurrency(work time.Duration) (func(manifestEntry) entryOutcome, func() map[string]int) {
+	var mu sync.Mutex
+	current := map[string]int{}
+	maxSeen := map[string]int{}
+
+	process := func(entry manifestEntry) entryOutcome {
+		mu.Lock()
+		current[entry.Owner]++
+		if current[entry.Owner] > maxSeen[entry.Owner] {
+			maxSeen[entry.Owner] = current[entry.Owner]
+		}
+		mu.Unlock()
+
+		time.Sleep(work)
+
+		mu.Lock()
+		current[entry.Owner]--
+		mu.Unlock()
+
+		return entryOutcome{}
+	}
+
+	read := func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		out := make(map[string]int, len(maxSeen))
+		for k, v := range maxSeen {
+			out[k] = v
+		}
+		return out
+	}
+
+	return process, read
+}
+
+func TestRunConcurrentEntries_RespectsOwnerConcurrency(t *testing.T) {
+	entries := []manifestEntry{
+		{Owner: "github", Number: 1},
+		{Owner: "github", Number: 2},
+		{Owner: "github", Number: 3},
+		{Owner: "github", Number: 4},
+		{Owner: "cli", Number: 1},
+		{Owner: "cli", Number: 2},
+	}
+
+	process, maxSeen := trackConcurrency(20 * time.Millisecond)
+
+	outcomes := runConcurrentEntries(10, 2, entries, process, nil)
+	assert.Len(t, outcomes, len(entries))
+
+	seen := maxSeen()
+	assert.LessOrEqual(t, seen["github"], 2)
+	assert.LessOrEqual(t, seen["cli"], 2)
+	// With a high global cap and 4 "github" entries, the owner cap should
+	// actually have been reached, not just trivially respected.
+	assert.Equal(t, 2, seen["github"])
+}
+
+func TestRunConcurrentEntries_RespectsGlobalConcurrency(t *testing.T) {
+	entries := make([]manifestEntry, 0, 6)
+	for i := 0; i < 6; i++ {
+		entries = append(entries, manifestEntry{Owner: string(rune('a' + i)), Number: int32(i + 1)})
+	}
+
+	var mu sync.Mutex
+	inFlight, maxSeen := 0, 0
+	process := func(entry manifestEntry) entryOutcome {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		return entryOutcome{}
+	}
+
+	runConcurrentEntries(3, 10, entries, process, nil)
+
+	assert.LessOrEqual(t, maxSeen, 3)
+	assert.Equal(t, 3, maxSeen)
+}
+
+func TestRunConcurrentEntries_PreservesOrder(t *testing.T) {
+	entries := []manifestEntry{
+		{Owner: "a", Number: 1},
+		{Owner: "b", Number: 2},
+		{Owner: "a", Number: 3},
+	}
+
+	outcomes := runConcurrentEntries(5, 5, entries, func(entry manifestEntry) entryOutcome {
+		// Entries complete out of submission order; the result slice must
+		// still line up with entries by index.
+		if entry.Owner == "a" {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return entryOutcome{mutated: queries.Project{Number: entry.Number}}
+	}, nil)
+
+	assert.Equal(t, int32(1), outcomes[0].mutated.Number)
+	assert.Equal(t, int32(2), outcomes[1].mutated.Number)
+	assert.Equal(t, int32(3), outcomes[2].mutated.Number)
+}
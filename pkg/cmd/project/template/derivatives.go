@@ -0,0 +1,184 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/tableprinter"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/client"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type derivativesOpts struct {
+	owner    string
+	number   int32
+	exporter cmdutil.Exporter
+	limit    int
+	after    string
+}
+
+type derivativesConfig struct {
+	client *queries.Client
+	opts   derivativesOpts
+	io     *iostreams.IOStreams
+}
+
+// newCmdDerivatives returns the read-only `gh project template derivatives`
+// subcommand, which lists every project that was created from a template
+// project via "use as template" — the visibility gap a template maintainer
+// needs closed before they consider unmarking a widely-used template.
+func newCmdDerivatives(f *cmdutil.Factory, runF func(config derivativesConfig) error) *cobra.Command {
+	opts := derivativesOpts{}
+	derivativesCmd := &cobra.Command{
+		Use:   "derivatives <number>",
+		Short: "List the projects derived from a template project",
+		Example: heredoc.Doc(`
+			# list every project created from the github org's project 1
+			gh project template derivatives 1 --owner github
+
+			# as JSON, for scripting
+			gh project template derivatives 1 --owner github --format json
+
+			# fetch 50 at a time, resuming from a previous page's cursor
+			gh project template derivatives 1 --owner github --limit 50 --after Y3Vyc29yOnYyOpHOAA==
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.owner == "" {
+				return cmdutil.FlagErrorf("--owner is required")
+			}
+
+			if opts.after != "" && opts.limit <= 0 {
+				return cmdutil.FlagErrorf("--after requires --limit")
+			}
+
+			number, err := parseProjectNumber(args[0])
+			if err != nil {
+				return cmdutil.FlagErrorf("%s", err)
+			}
+			opts.number = number
+
+			client, err := client.New(f)
+			if err != nil {
+				return err
+			}
+
+			config := derivativesConfig{
+				client: client,
+				opts:   opts,
+				io:     f.IOStreams,
+			}
+
+			if runF != nil {
+				return runF(config)
+			}
+			return runDerivatives(config)
+		},
+	}
+
+	derivativesCmd.Flags().StringVar(&opts.owner, "owner", "", "Login of the template project's organization.")
+	derivativesCmd.Flags().IntVar(&opts.limit, "limit", 0, "Fetch at most this many derived projects in a single page instead of fully paginating through every one. Pair with --after to resume a listing truncated by --limit from where it left off. 0 fetches everything.")
+	derivativesCmd.Flags().StringVar(&opts.after, "after", "", "Resume a --limit'd listing from this pagination `cursor`, as returned in a previous page's pageInfo.endCursor. Requires --limit.")
+	cmdutil.AddFormatFlags(derivativesCmd, &opts.exporter)
+
+	return derivativesCmd
+}
+
+func runDerivatives(config derivativesConfig) error {
+	if config.opts.limit > 0 {
+		return runDerivativesPage(config)
+	}
+
+	derivatives, err := config.client.DerivedProjects(config.opts.owner, config.opts.number)
+	if err != nil {
+		return fmt.Errorf("could not list derived projects: %w", err)
+	}
+
+	if config.opts.exporter != nil {
+		return config.opts.exporter.Write(config.io, derivatives.Nodes)
+	}
+
+	if len(derivatives.Nodes) == 0 {
+		_, err := fmt.Fprintln(config.io.Out, "no derived projects found")
+		return err
+	}
+
+	tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title", "Owner", "URL"))
+	for _, p := range derivatives.Nodes {
+		tp.AddField(strconv.Itoa(int(p.Number)), tableprinter.WithTruncate(nil))
+		tp.AddField(p.Title)
+		tp.AddField(p.OwnerLogin())
+		tp.AddField(p.URL)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+// derivativesPageExport is the --limit JSON export shape, carrying the
+// pageInfo a consumer needs to resume the listing with --after alongside
+// the page's nodes.
+type derivativesPageExport struct {
+	Nodes    []queries.DerivedProject
+	PageInfo queries.PageInfo
+}
+
+// ExportData renders the page as {"nodes": [...], "pageInfo": {...}},
+// reusing each node's own ExportData so the "nodes" entries match the shape
+// a fully-paginated `--format json` listing already produces.
+func (d derivativesPageExport) ExportData(fields []string) map[string]interface{} {
+	nodes := make([]interface{}, len(d.Nodes))
+	for i, n := range d.Nodes {
+		nodes[i] = n.ExportData(fields)
+	}
+	return map[string]interface{}{
+		"nodes": nodes,
+		"pageInfo": map[string]interface{}{
+			"hasNextPage": d.PageInfo.HasNextPage,
+			"endCursor":   string(d.PageInfo.EndCursor),
+		},
+	}
+}
+
+// runDerivativesPage handles --limit, fetching a single page instead of
+// DerivedProjects' full pagination, so a consumer can process a huge org's
+// derivatives incrementally across repeated invocations via --after.
+func runDerivativesPage(config derivativesConfig) error {
+	page, err := config.client.DerivedProjectsPage(config.opts.owner, config.opts.number, config.opts.limit, config.opts.after)
+	if err != nil {
+		return fmt.Errorf("could not list derived projects: %w", err)
+	}
+
+	if config.opts.exporter != nil {
+		return config.opts.exporter.Write(config.io, derivativesPageExport{
+			Nodes:    page.Nodes,
+			PageInfo: page.PageInfo,
+		})
+	}
+
+	if len(page.Nodes) == 0 {
+		_, err := fmt.Fprintln(config.io.Out, "no derived projects found")
+		return err
+	}
+
+	tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title", "Owner", "URL"))
+	for _, p := range page.Nodes {
+		tp.AddField(strconv.Itoa(int(p.Number)), tableprinter.WithTruncate(nil))
+		tp.AddField(p.Title)
+		tp.AddField(p.OwnerLogin())
+		tp.AddField(p.URL)
+		tp.EndRow()
+	}
+	if err := tp.Render(); err != nil {
+		return err
+	}
+
+	if page.PageInfo.HasNextPage {
+		_, err := fmt.Fprintf(config.io.ErrOut, "More results available; resume with --after %s\n", page.PageInfo.EndCursor)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRunDerivatives(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"name": "derivedProjects"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectDerivatives.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"derivedProjects": map[string]interface{}{
+							"totalCount": 1,
+							"pageInfo":   map[string]interface{}{"hasNextPage": false},
+							"nodes": []interface{}{
+								map[string]interface{}{
+									"number": 7,
+									"title":  "Team Roadmap",
+									"url":    "https://github.com/orgs/github/projects/7",
+									"owner": map[string]interface{}{
+										"__typename": "Organization",
+										"login":      "github",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	config := derivativesConfig{
+		opts:   derivativesOpts{owner: "github", number: 1},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runDerivatives(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "Team Roadmap")
+	assert.Contains(t, stdout.String(), "github")
+}
+
+func TestRunDerivatives_Unsupported(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2":    map[string]interface{}{"fields": []interface{}{}},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	config := derivativesConfig{
+		opts:   derivativesOpts{owner: "github", number: 1},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runDerivatives(config)
+	assert.EqualError(t, err, `could not list derived projects: this host's GraphQL schema does not expose derived projects`)
+}
+
+func TestRunDerivatives_LimitAndAfter(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"name": "derivedProjects"},
+					},
+				},
+			},
+		})
+
+	// first page: exactly one result and a cursor to resume from
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectDerivatives.*`).
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"query": "query ProjectDerivatives.*",
+			"variables": map[string]interface{}{
+				"login":  "github",
+				"number": 1,
+				"first":  1,
+				"after":  nil,
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"derivedProjects": map[string]interface{}{
+							"totalCount": 2,
+							"pageInfo":   map[string]interface{}{"hasNextPage": true, "endCursor": "cursor-1"},
+							"nodes": []interface{}{
+								map[string]interface{}{
+									"number": 7,
+									"title":  "Team Roadmap",
+									"url":    "https://github.com/orgs/github/projects/7",
+									"owner": map[string]interface{}{
+										"__typename": "Organization",
+										"login":      "github",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, stderr := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	config := derivativesConfig{
+		opts:   derivativesOpts{owner: "github", number: 1, limit: 1},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runDerivatives(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "Team Roadmap")
+	assert.Contains(t, stderr.String(), "More results available; resume with --after cursor-1")
+
+	// second page: resumed from cursor-1, no further pages left
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"name": "derivedProjects"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectDerivatives.*`).
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"query": "query ProjectDerivatives.*",
+			"variables": map[string]interface{}{
+				"login":  "github",
+				"number": 1,
+				"first":  1,
+				"after":  "cursor-1",
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"derivedProjects": map[string]interface{}{
+							"totalCount": 2,
+							"pageInfo":   map[string]interface{}{"hasNextPage": false},
+							"nodes": []interface{}{
+								map[string]interface{}{
+									"number": 9,
+									"title":  "Second Roadmap",
+									"url":    "https://github.com/orgs/github/projects/9",
+									"owner": map[string]interface{}{
+										"__typename": "Organization",
+										"login":      "github",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios2, _, stdout2, stderr2 := iostreams.Test()
+	ios2.SetStdoutTTY(true)
+	config2 := derivativesConfig{
+		opts:   derivativesOpts{owner: "github", number: 1, limit: 1, after: "cursor-1"},
+		client: queries.NewTestClient(),
+		io:     ios2,
+	}
+
+	err = runDerivatives(config2)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout2.String(), "Second Roadmap")
+	assert.Empty(t, stderr2.String())
+}
+
+func TestRunDerivatives_LimitJSON(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"name": "derivedProjects"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectDerivatives.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"derivedProjects": map[string]interface{}{
+							"totalCount": 2,
+							"pageInfo":   map[string]interface{}{"hasNextPage": true, "endCursor": "cursor-1"},
+							"nodes": []interface{}{
+								map[string]interface{}{
+									"number": 7,
+									"title":  "Team Roadmap",
+									"url":    "https://github.com/orgs/github/projects/7",
+									"owner": map[string]interface{}{
+										"__typename": "Organization",
+										"login":      "github",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := derivativesConfig{
+		opts:   derivativesOpts{owner: "github", number: 1, limit: 1, exporter: cmdutil.NewJSONExporter()},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runDerivatives(config)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"nodes":[{"number":7,"title":"Team Roadmap","url":"https://github.com/orgs/github/projects/7","owner":"github"}],"pageInfo":{"hasNextPage":true,"endCursor":"cursor-1"}}`, stdout.String())
+}
@@ -0,0 +1,90 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// matchResult records the outcome of templating one project matched by
+// --description-contains.
+type matchResult struct {
+	Project queries.Project `json:"project"`
+	Error   string          `json:"error,omitempty"`
+	Code    errorCode       `json:"code,omitempty"`
+}
+
+// runDescriptionMatch templates every project belonging to config.opts.owner
+// whose ShortDescription contains config.opts.descriptionContains, for
+// migrating orgs that marked templates by description convention before
+// real template flags existed. It is subject to the --confirm-threshold
+// guard like --all-orgs.
+func runDescriptionMatch(config templateConfig) error {
+	owner, err := resolveOwner(config)
+	if err != nil {
+		return err
+	}
+
+	projects, err := config.client.Projects(owner.Login, owner.Type, 0, false)
+	if err != nil {
+		return err
+	}
+
+	var matches []queries.Project
+	for _, p := range projects.Nodes {
+		if strings.Contains(p.ShortDescription, config.opts.descriptionContains) {
+			matches = append(matches, p)
+		}
+	}
+
+	if len(matches) == 0 {
+		return printEmptyProjectList(config)
+	}
+
+	preview := func() ([]queries.Project, error) {
+		return matches, nil
+	}
+
+	if err := confirmBulkOperation(config, len(matches), fmt.Sprintf("%d matching projects", len(matches)), preview); err != nil {
+		return err
+	}
+
+	summary := batchSummary{}
+	results := make([]matchResult, 0, len(matches))
+
+	for _, p := range matches {
+		entryConfig := config
+		entryConfig.opts.projectID = p.ID
+
+		mutated, _, noop, err := applyMutation(entryConfig, owner)
+		if err != nil {
+			summary.Failed++
+			results = append(results, matchResult{Project: p, Error: err.Error(), Code: classifyError(err)})
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case noop:
+			summary.Noop++
+		case config.opts.undo:
+			summary.Unmarked++
+		default:
+			summary.Marked++
+		}
+		results = append(results, matchResult{Project: mutated})
+	}
+
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, results); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, results)
+}
@@ -0,0 +1,175 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// stateDriftAddition is a project present in the current snapshot but not
+// recorded in the baseline dump, e.g. one created since the baseline was
+// taken.
+type stateDriftAddition struct {
+	Number int32 `json:"number"`
+	Now    bool  `json:"now"`
+}
+
+// stateDriftRemoval is a project recorded in the baseline dump but absent
+// from the current snapshot, e.g. one deleted since the baseline was taken.
+type stateDriftRemoval struct {
+	Number int32 `json:"number"`
+	Was    bool  `json:"was"`
+}
+
+// stateDriftChange is a project present in both snapshots whose template
+// flag differs between them.
+type stateDriftChange struct {
+	Number int32 `json:"number"`
+	Was    bool  `json:"was"`
+	Now    bool  `json:"now"`
+}
+
+// stateDrift is the result of comparing a baseline --dump-state snapshot
+// against an owner's current template state, for drift detection.
+type stateDrift struct {
+	Owner   string               `json:"owner"`
+	Added   []stateDriftAddition `json:"added,omitempty"`
+	Removed []stateDriftRemoval  `json:"removed,omitempty"`
+	Changed []stateDriftChange   `json:"changed,omitempty"`
+}
+
+// hasDrift reports whether the comparison found any difference at all.
+func (d stateDrift) hasDrift() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// diffStateDumps compares baseline against current and returns the drift
+// between them: projects added or removed since the baseline was taken, and
+// projects present in both whose template flag changed. Every slice is
+// sorted by project number.
+func diffStateDumps(baseline, current templateStateDump) stateDrift {
+	drift := stateDrift{Owner: current.Owner}
+
+	for raw, now := range current.Projects {
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			continue
+		}
+		was, ok := baseline.Projects[raw]
+		if !ok {
+			drift.Added = append(drift.Added, stateDriftAddition{Number: int32(number), Now: now})
+			continue
+		}
+		if was != now {
+			drift.Changed = append(drift.Changed, stateDriftChange{Number: int32(number), Was: was, Now: now})
+		}
+	}
+
+	for raw, was := range baseline.Projects {
+		if _, ok := current.Projects[raw]; ok {
+			continue
+		}
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			continue
+		}
+		drift.Removed = append(drift.Removed, stateDriftRemoval{Number: int32(number), Was: was})
+	}
+
+	sort.Slice(drift.Added, func(i, j int) bool { return drift.Added[i].Number < drift.Added[j].Number })
+	sort.Slice(drift.Removed, func(i, j int) bool { return drift.Removed[i].Number < drift.Removed[j].Number })
+	sort.Slice(drift.Changed, func(i, j int) bool { return drift.Changed[i].Number < drift.Changed[j].Number })
+
+	return drift
+}
+
+// printDrift renders drift as a human-readable report: one line per added,
+// removed, or changed project, grouped under a heading for each category
+// that has at least one entry.
+func printDrift(config templateConfig, drift stateDrift) error {
+	if config.opts.quiet {
+		return nil
+	}
+
+	out := config.io.Out
+
+	if len(drift.Added) > 0 {
+		if _, err := fmt.Fprintln(out, "Added (present now, not in the baseline):"); err != nil {
+			return err
+		}
+		for _, a := range drift.Added {
+			if _, err := fmt.Fprintf(out, "  + project %d (template=%t)\n", a.Number, a.Now); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(drift.Removed) > 0 {
+		if _, err := fmt.Fprintln(out, "Removed (in the baseline, not present now):"); err != nil {
+			return err
+		}
+		for _, r := range drift.Removed {
+			if _, err := fmt.Fprintf(out, "  - project %d (template=%t)\n", r.Number, r.Was); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(drift.Changed) > 0 {
+		if _, err := fmt.Fprintln(out, "Changed (template flag differs from the baseline):"); err != nil {
+			return err
+		}
+		for _, c := range drift.Changed {
+			if _, err := fmt.Fprintf(out, "  ~ project %d: %t -> %t\n", c.Number, c.Was, c.Now); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !drift.hasDrift() {
+		if _, err := fmt.Fprintln(out, "No drift: current state matches the baseline."); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runDiffAgainst compares the current template flags of --diff-against's
+// owner (taken from the baseline dump itself, the same convention
+// --restore-state uses) against a previously dumped state, without
+// mutating anything. It reports additions, removals, and changes and, by
+// default, exits non-zero if any drift is found; --no-fail-on-drift
+// reports the same diff but always exits zero, for monitoring that only
+// wants the data and handles alerting itself.
+func runDiffAgainst(config templateConfig) error {
+	baseline, err := loadStateDump(config.opts.diffAgainst, config)
+	if err != nil {
+		return err
+	}
+
+	current, err := buildStateDump(config.client, baseline.Owner, config.opts.now())
+	if err != nil {
+		return err
+	}
+
+	drift := diffStateDumps(baseline, current)
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, drift); err != nil {
+			return err
+		}
+	} else if err := printDrift(config, drift); err != nil {
+		return err
+	}
+
+	if err := writeOutputFile(config, drift); err != nil {
+		return err
+	}
+
+	if drift.hasDrift() && !config.opts.noFailOnDrift {
+		return fmt.Errorf("drift detected: %d added, %d removed, %d changed", len(drift.Added), len(drift.Removed), len(drift.Changed))
+	}
+
+	return nil
+}
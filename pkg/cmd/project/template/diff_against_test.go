@@ -0,0 +1,175 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestDiffStateDumps(t *testing.T) {
+	baseline := templateStateDump{
+		Owner: "github",
+		Projects: map[string]bool{
+			"1": true,  // unchanged
+			"2": false, // flips to true below
+			"3": true,  // deleted below
+		},
+	}
+	current := templateStateDump{
+		Owner: "github",
+		Projects: map[string]bool{
+			"1": true,
+			"2": true,
+			"4": false, // created since the baseline
+		},
+	}
+
+	drift := diffStateDumps(baseline, current)
+	assert.Equal(t, stateDrift{
+		Owner:   "github",
+		Added:   []stateDriftAddition{{Number: 4, Now: false}},
+		Removed: []stateDriftRemoval{{Number: 3, Was: true}},
+		Changed: []stateDriftChange{{Number: 2, Was: false, Now: true}},
+	}, drift)
+	assert.True(t, drift.hasDrift())
+}
+
+func TestDiffStateDumps_NoDrift(t *testing.T) {
+	dump := templateStateDump{Owner: "github", Projects: map[string]bool{"1": true}}
+
+	drift := diffStateDumps(dump, dump)
+	assert.False(t, drift.hasDrift())
+	assert.Empty(t, drift.Added)
+	assert.Empty(t, drift.Removed)
+	assert.Empty(t, drift.Changed)
+}
+
+func TestRunDiffAgainst_DriftDetected(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectsV2": map[string]interface{}{
+						"totalCount": 2,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"title": "Roadmap", "id": "roadmap ID", "number": 1},
+							map[string]interface{}{"title": "Backlog", "id": "backlog ID", "number": 2},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes": []interface{}{
+							map[string]interface{}{
+								"number":    1,
+								"title":     "Roadmap",
+								"url":       "https://github.com/orgs/github/projects/1",
+								"public":    true,
+								"updatedAt": "2024-01-02T15:04:05Z",
+								"items":     map[string]interface{}{"totalCount": 0},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/baseline.json"
+	writeTestFile(t, path, `{"owner":"github","timestamp":"2024-01-01T00:00:00Z","projects":{"1":false,"3":true}}`)
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{diffAgainst: path, now: time.Now},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runDiffAgainst(config)
+	assert.EqualError(t, err, "drift detected: 1 added, 1 removed, 1 changed")
+	assert.Contains(t, stdout.String(), "Added (present now, not in the baseline):")
+	assert.Contains(t, stdout.String(), "  + project 2 (template=false)\n")
+	assert.Contains(t, stdout.String(), "Removed (in the baseline, not present now):")
+	assert.Contains(t, stdout.String(), "  - project 3 (template=true)\n")
+	assert.Contains(t, stdout.String(), "Changed (template flag differs from the baseline):")
+	assert.Contains(t, stdout.String(), "  ~ project 1: false -> true\n")
+}
+
+func TestRunDiffAgainst_NoFailOnDrift(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectsV2": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"title": "Roadmap", "id": "roadmap ID", "number": 1},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 0,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes":      []interface{}{},
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/baseline.json"
+	writeTestFile(t, path, `{"owner":"github","timestamp":"2024-01-01T00:00:00Z","projects":{"1":true}}`)
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{diffAgainst: path, noFailOnDrift: true, quiet: true, now: time.Now},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runDiffAgainst(config)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,78 @@
+package template
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// templateStateDump is the document written by `template audit
+// --dump-state`: a lightweight snapshot of which of an owner's projects are
+// currently templates, cheaper to produce than a full export and meant to
+// be read back later to restore template flags to a known good state.
+type templateStateDump struct {
+	Owner     string          `json:"owner"`
+	Timestamp string          `json:"timestamp"`
+	Projects  map[string]bool `json:"projects"`
+}
+
+// buildStateDump queries owner's full project list and template gallery and
+// combines them into a templateStateDump, the shared snapshot shape used by
+// `template audit --dump-state`, `--restore-state`, and `--diff-against`. A
+// project's template flag is derived from whether it appears in the owner's
+// template gallery (TemplateProjects) rather than from a per-project boolean
+// field, since Project.Template is deliberately not queried for GHES
+// compatibility (see the comment on queries.Project).
+func buildStateDump(client *queries.Client, owner string, now time.Time) (templateStateDump, error) {
+	projects, err := client.Projects(owner, queries.OrgOwner, 0, false)
+	if err != nil {
+		return templateStateDump{}, err
+	}
+
+	templates, err := client.TemplateProjects(owner)
+	if err != nil {
+		return templateStateDump{}, err
+	}
+
+	isTemplate := make(map[int32]bool, len(templates.Nodes))
+	for _, t := range templates.Nodes {
+		isTemplate[t.Number] = true
+	}
+
+	dump := templateStateDump{
+		Owner:     owner,
+		Timestamp: now.UTC().Format(time.RFC3339),
+		Projects:  make(map[string]bool, len(projects.Nodes)),
+	}
+	for _, p := range projects.Nodes {
+		dump.Projects[strconv.Itoa(int(p.Number))] = isTemplate[p.Number]
+	}
+
+	return dump, nil
+}
+
+// runDumpState writes a templateStateDump for config.opts.owner to
+// config.opts.output (or stdout).
+func runDumpState(config auditConfig) error {
+	dump, err := buildStateDump(config.client, config.opts.owner, config.opts.now())
+	if err != nil {
+		return err
+	}
+
+	indent := jsonIndent(config.opts.indent)
+
+	if config.opts.output == "" {
+		enc := json.NewEncoder(config.io.Out)
+		enc.SetIndent("", indent)
+		return enc.Encode(dump)
+	}
+
+	return atomicWriteFile(config.opts.output, config.opts.gzip, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", indent)
+		return enc.Encode(dump)
+	})
+}
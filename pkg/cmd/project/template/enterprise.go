@@ -0,0 +1,18 @@
+package template
+
+import "fmt"
+
+// runEnterprise applies the template operation to a project matching
+// config.opts.number or config.opts.title in every organization belonging
+// to the enterprise identified by config.opts.enterprise. It shares its
+// batch logic with --all-orgs via runOrgsBatch, differing only in how the
+// set of organizations is gathered, and is subject to the same
+// --confirm-threshold guard.
+func runEnterprise(config templateConfig) error {
+	orgs, err := config.client.EnterpriseOrgs(config.opts.enterprise)
+	if err != nil {
+		return fmt.Errorf("could not list organizations for enterprise %q: %w", config.opts.enterprise, err)
+	}
+
+	return runOrgsBatch(config, orgs, fmt.Sprintf("a matching project across all %d organizations in enterprise %q", len(orgs), config.opts.enterprise))
+}
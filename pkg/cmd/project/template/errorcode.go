@@ -0,0 +1,91 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/cli/cli/v2/api"
+)
+
+// errorCode is a stable, enumerated classification of an error that
+// occurred while processing one entry of a batch operation, meant for
+// tooling consuming --format json to branch on reliably instead of
+// matching against message text, which can change between gh releases.
+// The zero value, errorCodeUnknown, covers anything this package doesn't
+// have a specific classification for.
+type errorCode string
+
+const (
+	errorCodeNotFound    errorCode = "not_found"
+	errorCodePermission  errorCode = "permission"
+	errorCodeRateLimit   errorCode = "ratelimit"
+	errorCodeTimeout     errorCode = "timeout"
+	errorCodeSSORequired errorCode = "sso_required"
+	errorCodeConflict    errorCode = "conflict"
+	errorCodeUnknown     errorCode = "unknown"
+)
+
+// classifyError maps err to an errorCode, inspecting it in the same order a
+// human reading the error would: a context deadline first (the least
+// ambiguous), then the API error types that carry a GraphQL error type or
+// an HTTP status code, then a best-effort scan of the message text for SSO
+// enforcement, which isn't otherwise distinguishable from a plain
+// permission error by type or status code alone.
+func classifyError(err error) errorCode {
+	if err == nil {
+		return ""
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errorCodeTimeout
+	}
+
+	var gerr api.GraphQLError
+	if errors.As(err, &gerr) {
+		for _, e := range gerr.Errors {
+			switch e.Type {
+			case "NOT_FOUND":
+				return errorCodeNotFound
+			case "FORBIDDEN", "INSUFFICIENT_SCOPES":
+				return errorCodePermission
+			case "RATE_LIMITED":
+				return errorCodeRateLimit
+			}
+		}
+	}
+
+	var herr api.HTTPError
+	if errors.As(err, &herr) {
+		switch herr.StatusCode {
+		case http.StatusNotFound:
+			return errorCodeNotFound
+		case http.StatusTooManyRequests:
+			return errorCodeRateLimit
+		case http.StatusUnauthorized, http.StatusForbidden:
+			if strings.Contains(strings.ToLower(herr.Message), "saml") || strings.Contains(strings.ToLower(herr.Message), "sso") {
+				return errorCodeSSORequired
+			}
+			return errorCodePermission
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "concurrent modification detected"):
+		return errorCodeConflict
+	case strings.Contains(msg, "saml") || strings.Contains(msg, "sso"):
+		return errorCodeSSORequired
+	case strings.Contains(msg, "rate limit"):
+		return errorCodeRateLimit
+	case strings.Contains(msg, "timed out") || strings.Contains(msg, "deadline exceeded"):
+		return errorCodeTimeout
+	case strings.Contains(msg, "not found") || strings.Contains(msg, "could not resolve"):
+		return errorCodeNotFound
+	case strings.Contains(msg, "permission") || strings.Contains(msg, "forbidden") || strings.Contains(msg, "scope"):
+		return errorCodePermission
+	}
+
+	return errorCodeUnknown
+}
@@ -0,0 +1,92 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cli/cli/v2/api"
+	ghAPI "github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errorCode
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "context deadline exceeded",
+			err:  fmt.Errorf("could not verify template change: %w", context.DeadlineExceeded),
+			want: errorCodeTimeout,
+		},
+		{
+			name: "graphql not found",
+			err:  api.GraphQLError{GraphQLError: &ghAPI.GraphQLError{Errors: []ghAPI.GraphQLErrorItem{{Type: "NOT_FOUND", Message: "Could not resolve to a ProjectV2"}}}},
+			want: errorCodeNotFound,
+		},
+		{
+			name: "graphql forbidden",
+			err:  api.GraphQLError{GraphQLError: &ghAPI.GraphQLError{Errors: []ghAPI.GraphQLErrorItem{{Type: "FORBIDDEN", Message: "Resource not accessible"}}}},
+			want: errorCodePermission,
+		},
+		{
+			name: "graphql insufficient scopes",
+			err:  api.GraphQLError{GraphQLError: &ghAPI.GraphQLError{Errors: []ghAPI.GraphQLErrorItem{{Type: "INSUFFICIENT_SCOPES", Message: "missing project scope"}}}},
+			want: errorCodePermission,
+		},
+		{
+			name: "graphql rate limited",
+			err:  api.GraphQLError{GraphQLError: &ghAPI.GraphQLError{Errors: []ghAPI.GraphQLErrorItem{{Type: "RATE_LIMITED", Message: "API rate limit exceeded"}}}},
+			want: errorCodeRateLimit,
+		},
+		{
+			name: "http 404",
+			err:  api.HTTPError{HTTPError: &ghAPI.HTTPError{StatusCode: 404, Message: "Not Found"}},
+			want: errorCodeNotFound,
+		},
+		{
+			name: "http 429",
+			err:  api.HTTPError{HTTPError: &ghAPI.HTTPError{StatusCode: 429, Message: "Too Many Requests"}},
+			want: errorCodeRateLimit,
+		},
+		{
+			name: "http 403 plain",
+			err:  api.HTTPError{HTTPError: &ghAPI.HTTPError{StatusCode: 403, Message: "Must have admin rights"}},
+			want: errorCodePermission,
+		},
+		{
+			name: "http 403 saml",
+			err:  api.HTTPError{HTTPError: &ghAPI.HTTPError{StatusCode: 403, Message: "Resource protected by organization SAML enforcement"}},
+			want: errorCodeSSORequired,
+		},
+		{
+			name: "message-only sso",
+			err:  errors.New("you must authorize your personal access token to access this organization's SSO-protected resources"),
+			want: errorCodeSSORequired,
+		},
+		{
+			name: "message-only unrecognized",
+			err:  errors.New("something went sideways"),
+			want: errorCodeUnknown,
+		},
+		{
+			name: "concurrent modification",
+			err:  errors.New("concurrent modification detected: expected project PVT_1 but read back PVT_2 after 4 attempts; another process may have changed this project concurrently"),
+			want: errorCodeConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyError(tt.err))
+		})
+	}
+}
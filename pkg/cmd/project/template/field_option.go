@@ -0,0 +1,150 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// fieldOptionMatch records the outcome of templating one project matched by
+// --field-option.
+type fieldOptionMatch struct {
+	Project queries.Project `json:"project"`
+	Error   string          `json:"error,omitempty"`
+	Code    errorCode       `json:"code,omitempty"`
+}
+
+// parseFieldOption splits raw ("FieldName=Option") into the single-select
+// field name to look up and the option name --field-option is matching
+// against.
+func parseFieldOption(raw string) (string, string, error) {
+	name, option, ok := strings.Cut(raw, "=")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf(`expected the "FIELD=OPTION" format, got %q`, raw)
+	}
+	return name, option, nil
+}
+
+// projectHasFieldOption reports whether any item in project has its
+// single-select field fieldName set to the option named optionName. Unlike
+// projectHasFieldValue, which matches any field type's text representation,
+// this only inspects ProjectV2ItemFieldSingleSelectValue entries, since
+// --field-option is specifically about the option an org picked from a
+// single-select field's fixed list, not an arbitrary text/number/date value
+// that happens to render the same way. ok is false when none of project's
+// items have fieldName set as a single-select field at all.
+func projectHasFieldOption(project queries.Project, fieldName, optionName string) (matches bool, ok bool) {
+	for _, item := range project.Items.Nodes {
+		for _, fv := range item.FieldValues.Nodes {
+			if fv.Type != "ProjectV2ItemFieldSingleSelectValue" {
+				continue
+			}
+			if fv.ProjectV2ItemFieldSingleSelectValue.Field.Name() != fieldName {
+				continue
+			}
+			ok = true
+			if fv.ProjectV2ItemFieldSingleSelectValue.Name == optionName {
+				return true, true
+			}
+		}
+	}
+	return false, ok
+}
+
+// runFieldOption templates every project belonging to config.opts.owner whose
+// config.opts.fieldOption single-select field ("FIELD=OPTION") is set to the
+// named option on at least one item, for orgs that categorize projects via a
+// single-select field instead of a free-text value. Resolving field options
+// requires one additional request per project, so like --field-value this
+// always requires confirmation (or --yes) rather than only above
+// --confirm-threshold.
+func runFieldOption(config templateConfig) error {
+	fieldName, optionName, err := parseFieldOption(config.opts.fieldOption)
+	if err != nil {
+		return err
+	}
+
+	owner, err := resolveOwner(config)
+	if err != nil {
+		return err
+	}
+
+	projects, err := config.client.Projects(owner.Login, owner.Type, 0, false)
+	if err != nil {
+		return err
+	}
+
+	if len(projects.Nodes) == 0 {
+		return printEmptyProjectList(config)
+	}
+
+	if err := confirmExpensiveScan(config, len(projects.Nodes)); err != nil {
+		return err
+	}
+
+	var matches []queries.Project
+	var missingField int
+	for _, p := range projects.Nodes {
+		detailed, err := config.client.ProjectItems(owner, p.Number, 0)
+		if err != nil {
+			return fmt.Errorf("reading field options for project %d: %w", p.Number, err)
+		}
+
+		matched, ok := projectHasFieldOption(*detailed, fieldName, optionName)
+		if !ok {
+			missingField++
+			continue
+		}
+		if matched {
+			matches = append(matches, p)
+		}
+	}
+
+	if config.io.IsStderrTTY() && !config.opts.quiet {
+		fmt.Fprintf(config.io.ErrOut, "%d of %d projects have field %q; %d matched %q=%q\n",
+			len(projects.Nodes)-missingField, len(projects.Nodes), fieldName, len(matches), fieldName, optionName)
+	}
+
+	if len(matches) == 0 {
+		return printEmptyProjectList(config)
+	}
+
+	summary := batchSummary{}
+	results := make([]fieldOptionMatch, 0, len(matches))
+
+	for _, p := range matches {
+		entryConfig := config
+		entryConfig.opts.projectID = p.ID
+
+		mutated, _, noop, err := applyMutation(entryConfig, owner)
+		if err != nil {
+			summary.Failed++
+			results = append(results, fieldOptionMatch{Project: p, Error: err.Error(), Code: classifyError(err)})
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case noop:
+			summary.Noop++
+		case config.opts.undo:
+			summary.Unmarked++
+		default:
+			summary.Marked++
+		}
+		results = append(results, fieldOptionMatch{Project: mutated})
+	}
+
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, results); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, results)
+}
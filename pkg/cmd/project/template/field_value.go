@@ -0,0 +1,189 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+)
+
+// fieldValueMatch records the outcome of templating one project matched by
+// --field-value.
+type fieldValueMatch struct {
+	Project queries.Project `json:"project"`
+	Error   string          `json:"error,omitempty"`
+	Code    errorCode       `json:"code,omitempty"`
+}
+
+// parseFieldValue splits raw ("FieldName=Value") into the field name to
+// look up and the value --field-value is matching against.
+func parseFieldValue(raw string) (string, string, error) {
+	name, value, ok := strings.Cut(raw, "=")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf(`expected the "FIELD=VALUE" format, got %q`, raw)
+	}
+	return name, value, nil
+}
+
+// fieldValueNodeText returns the name of the field a FieldValueNodes belongs
+// to and its value rendered as text, for the field types that can
+// meaningfully hold an external ID. Field types with no single scalar
+// representation (users, reviewers, pull requests) are skipped.
+func fieldValueNodeText(v queries.FieldValueNodes) (name, value string) {
+	switch v.Type {
+	case "ProjectV2ItemFieldTextValue":
+		return v.ProjectV2ItemFieldTextValue.Field.Name(), v.ProjectV2ItemFieldTextValue.Text
+	case "ProjectV2ItemFieldSingleSelectValue":
+		return v.ProjectV2ItemFieldSingleSelectValue.Field.Name(), v.ProjectV2ItemFieldSingleSelectValue.Name
+	case "ProjectV2ItemFieldNumberValue":
+		return v.ProjectV2ItemFieldNumberValue.Field.Name(), fmt.Sprintf("%g", v.ProjectV2ItemFieldNumberValue.Number)
+	case "ProjectV2ItemFieldDateValue":
+		return v.ProjectV2ItemFieldDateValue.Field.Name(), v.ProjectV2ItemFieldDateValue.Date
+	}
+	return "", ""
+}
+
+// projectHasFieldValue reports whether any item in project has fieldName set
+// to value. ProjectV2 field values live on items rather than on the project
+// itself, so every item has to be checked; ok is false when none of
+// project's items have fieldName set at all (as opposed to set to a
+// different value).
+func projectHasFieldValue(project queries.Project, fieldName, value string) (matches bool, ok bool) {
+	for _, item := range project.Items.Nodes {
+		for _, fv := range item.FieldValues.Nodes {
+			name, text := fieldValueNodeText(fv)
+			if name != fieldName {
+				continue
+			}
+			ok = true
+			if text == value {
+				return true, true
+			}
+		}
+	}
+	return false, ok
+}
+
+// runFieldValue templates every project belonging to config.opts.owner whose
+// config.opts.fieldValue field ("FIELD=VALUE") matches on at least one item,
+// for orgs that tag projects with an external system ID via a custom field.
+// Resolving field values requires one additional request per project, so
+// unlike --all-orgs and --description-contains this always requires
+// confirmation (or --yes) rather than only above --confirm-threshold.
+func runFieldValue(config templateConfig) error {
+	fieldName, fieldValue, err := parseFieldValue(config.opts.fieldValue)
+	if err != nil {
+		return err
+	}
+
+	owner, err := resolveOwner(config)
+	if err != nil {
+		return err
+	}
+
+	projects, err := config.client.Projects(owner.Login, owner.Type, 0, false)
+	if err != nil {
+		return err
+	}
+
+	if len(projects.Nodes) == 0 {
+		return printEmptyProjectList(config)
+	}
+
+	if err := confirmExpensiveScan(config, len(projects.Nodes)); err != nil {
+		return err
+	}
+
+	var matches []queries.Project
+	var missingField int
+	for _, p := range projects.Nodes {
+		detailed, err := config.client.ProjectItems(owner, p.Number, 0)
+		if err != nil {
+			return fmt.Errorf("reading field values for project %d: %w", p.Number, err)
+		}
+
+		matched, ok := projectHasFieldValue(*detailed, fieldName, fieldValue)
+		if !ok {
+			missingField++
+			continue
+		}
+		if matched {
+			matches = append(matches, p)
+		}
+	}
+
+	if config.io.IsStderrTTY() && !config.opts.quiet {
+		fmt.Fprintf(config.io.ErrOut, "%d of %d projects have field %q; %d matched %q=%q\n",
+			len(projects.Nodes)-missingField, len(projects.Nodes), fieldName, len(matches), fieldName, fieldValue)
+	}
+
+	if len(matches) == 0 {
+		return printEmptyProjectList(config)
+	}
+
+	summary := batchSummary{}
+	results := make([]fieldValueMatch, 0, len(matches))
+
+	for _, p := range matches {
+		entryConfig := config
+		entryConfig.opts.projectID = p.ID
+
+		mutated, _, noop, err := applyMutation(entryConfig, owner)
+		if err != nil {
+			summary.Failed++
+			results = append(results, fieldValueMatch{Project: p, Error: err.Error(), Code: classifyError(err)})
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case noop:
+			summary.Noop++
+		case config.opts.undo:
+			summary.Unmarked++
+		default:
+			summary.Marked++
+		}
+		results = append(results, fieldValueMatch{Project: mutated})
+	}
+
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, results); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, results)
+}
+
+// confirmExpensiveScan unconditionally requires confirmation (or --yes when
+// the command cannot prompt) before resolving field values for count
+// projects one at a time, since each resolution is its own GraphQL request
+// regardless of how many projects ultimately match.
+func confirmExpensiveScan(config templateConfig, count int) error {
+	if err := checkMaxAffected(config, count); err != nil {
+		return err
+	}
+
+	if config.opts.yes {
+		return nil
+	}
+
+	if !config.io.CanPrompt() {
+		return cmdutil.FlagErrorf("--yes is required to resolve field values across %d projects", count)
+	}
+
+	confirmed, err := config.prompter.Confirm(fmt.Sprintf("This will read field values from %d projects one at a time, which may take a while. Continue?", count), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cmdutil.CancelError
+	}
+	return nil
+}
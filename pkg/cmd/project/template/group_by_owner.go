@@ -0,0 +1,215 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cli/cli/v2/internal/tableprinter"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// isMultiOwnerOperation reports whether opts describes a batch operation
+// whose results can span more than one owner -- the only case
+// --group-by-owner has anything to group. --from-file/--range entries can
+// each name a different --owner, --all-orgs/--my-orgs/--enterprise run one
+// project per organization, and --search's matches aren't scoped to an
+// owner at all, and --all-linked's matches are a repository's linked
+// projects, which can themselves belong to different owners. --undo-log's
+// entries come from a recorded operation log, which like --from-file can
+// span any number of owners. The remaining bulk selectors
+// (--description-contains, --field-value, --restore-state) are always
+// scoped to a single --owner, so grouping them would just produce a map
+// with one key.
+func isMultiOwnerOperation(opts templateOpts) bool {
+	return opts.fromFile != "" || opts.rangeExpr != "" || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.search != "" || opts.allLinked || opts.undoLog != ""
+}
+
+// groupProjectsByOwner groups projects by their owner's login, preserving
+// each owner's relative order. It backs --group-by-owner for --from-file
+// and --range, whose successful results are a plain []queries.Project.
+func groupProjectsByOwner(projects []queries.Project) map[string][]queries.Project {
+	grouped := make(map[string][]queries.Project)
+	for _, p := range projects {
+		grouped[p.OwnerLogin()] = append(grouped[p.OwnerLogin()], p)
+	}
+	return grouped
+}
+
+// groupOrgResultsByOwner groups --all-orgs/--my-orgs/--enterprise results by
+// the Owner field already recorded on each orgResult, rather than by the
+// mutated project's own owner, since a skipped or failed entry never
+// resolves a project to read an owner back from.
+func groupOrgResultsByOwner(results []orgResult) map[string][]orgResult {
+	grouped := make(map[string][]orgResult)
+	for _, r := range results {
+		grouped[r.Owner] = append(grouped[r.Owner], r)
+	}
+	return grouped
+}
+
+// groupSearchMatchesByOwner groups --search results by the matched
+// project's owner login.
+func groupSearchMatchesByOwner(matches []searchMatch) map[string][]searchMatch {
+	grouped := make(map[string][]searchMatch)
+	for _, m := range matches {
+		grouped[m.Project.OwnerLogin()] = append(grouped[m.Project.OwnerLogin()], m)
+	}
+	return grouped
+}
+
+// printGroupedProjects writes one header line plus a Number/Title table per
+// owner in grouped to stdout, sorted by owner login for a stable order. It
+// is a no-op when stdout isn't a TTY, the same way the rest of this package
+// leaves machine-readable output to --format/--output instead.
+func printGroupedProjects(config templateConfig, grouped map[string][]queries.Project) error {
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	for _, owner := range sortedOwners(grouped) {
+		if _, err := fmt.Fprintf(config.io.Out, "%s:\n", owner); err != nil {
+			return err
+		}
+		tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title"))
+		for _, p := range grouped[owner] {
+			tp.AddField(strconv.Itoa(int(p.Number)), tableprinter.WithTruncate(nil))
+			tp.AddField(p.Title)
+			tp.EndRow()
+		}
+		if err := tp.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printGroupedOrgResults writes one header line plus a Number/Title/Status
+// table per owner in grouped to stdout, sorted by owner login. Status is
+// "marked"/"unmarked", "skipped", or the entry's error, so a failed or
+// skipped org is still visible in its owner's section instead of silently
+// missing from the table.
+func printGroupedOrgResults(config templateConfig, grouped map[string][]orgResult) error {
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	for _, owner := range sortedOwners(grouped) {
+		if _, err := fmt.Fprintf(config.io.Out, "%s:\n", owner); err != nil {
+			return err
+		}
+		tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title", "Status"))
+		for _, r := range grouped[owner] {
+			tp.AddField(strconv.Itoa(int(r.Project.Number)), tableprinter.WithTruncate(nil))
+			tp.AddField(r.Project.Title)
+			tp.AddField(orgResultStatus(config, r))
+			tp.EndRow()
+		}
+		if err := tp.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orgResultStatus(config templateConfig, r orgResult) string {
+	switch {
+	case r.Error != "":
+		return r.Error
+	case r.Skipped:
+		return "skipped"
+	case config.opts.undo:
+		return "unmarked"
+	default:
+		return "marked"
+	}
+}
+
+// printGroupedSearchMatches writes one header line plus a Number/Title/
+// Status table per owner in grouped to stdout, sorted by owner login.
+func printGroupedSearchMatches(config templateConfig, grouped map[string][]searchMatch) error {
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	for _, owner := range sortedOwners(grouped) {
+		if _, err := fmt.Fprintf(config.io.Out, "%s:\n", owner); err != nil {
+			return err
+		}
+		tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title", "Status"))
+		for _, m := range grouped[owner] {
+			tp.AddField(strconv.Itoa(int(m.Project.Number)), tableprinter.WithTruncate(nil))
+			tp.AddField(m.Project.Title)
+			tp.AddField(searchMatchStatus(config, m))
+			tp.EndRow()
+		}
+		if err := tp.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func searchMatchStatus(config templateConfig, m searchMatch) string {
+	if m.Error != "" {
+		return m.Error
+	}
+	if config.opts.undo {
+		return "unmarked"
+	}
+	return "marked"
+}
+
+// groupLinkedMatchesByOwner groups --all-linked results by the matched
+// project's owner login.
+func groupLinkedMatchesByOwner(matches []linkedMatch) map[string][]linkedMatch {
+	grouped := make(map[string][]linkedMatch)
+	for _, m := range matches {
+		grouped[m.Project.OwnerLogin()] = append(grouped[m.Project.OwnerLogin()], m)
+	}
+	return grouped
+}
+
+// printGroupedLinkedMatches writes one header line plus a Number/Title/
+// Status table per owner in grouped to stdout, sorted by owner login.
+func printGroupedLinkedMatches(config templateConfig, grouped map[string][]linkedMatch) error {
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	for _, owner := range sortedOwners(grouped) {
+		if _, err := fmt.Fprintf(config.io.Out, "%s:\n", owner); err != nil {
+			return err
+		}
+		tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title", "Status"))
+		for _, m := range grouped[owner] {
+			tp.AddField(strconv.Itoa(int(m.Project.Number)), tableprinter.WithTruncate(nil))
+			tp.AddField(m.Project.Title)
+			tp.AddField(linkedMatchStatus(config, m))
+			tp.EndRow()
+		}
+		if err := tp.Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func linkedMatchStatus(config templateConfig, m linkedMatch) string {
+	if m.Error != "" {
+		return m.Error
+	}
+	if config.opts.undo {
+		return "unmarked"
+	}
+	return "marked"
+}
+
+func sortedOwners[T any](grouped map[string]T) []string {
+	owners := make([]string, 0, len(grouped))
+	for owner := range grouped {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	return owners
+}
@@ -0,0 +1,198 @@
+package template
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestIsMultiOwnerOperation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts templateOpts
+		want bool
+	}{
+		{name: "from-file", opts: templateOpts{fromFile: "manifest.json"}, want: true},
+		{name: "range", opts: templateOpts{rangeExpr: "1-10"}, want: true},
+		{name: "all-orgs", opts: templateOpts{allOrgs: true}, want: true},
+		{name: "my-orgs", opts: templateOpts{myOrgs: true}, want: true},
+		{name: "enterprise", opts: templateOpts{enterprise: "my-enterprise"}, want: true},
+		{name: "search", opts: templateOpts{search: "org:github is:open"}, want: true},
+		{name: "undo-log", opts: templateOpts{undoLog: "ops.jsonl"}, want: true},
+		{name: "single project", opts: templateOpts{owner: "github", number: 1}, want: false},
+		{name: "description-contains", opts: templateOpts{descriptionContains: "[template]"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isMultiOwnerOperation(tt.opts))
+		})
+	}
+}
+
+func TestGroupProjectsByOwner(t *testing.T) {
+	projects := []queries.Project{
+		projectOwnedBy("github", 1),
+		projectOwnedBy("monalisa", 2),
+		projectOwnedBy("github", 3),
+	}
+
+	grouped := groupProjectsByOwner(projects)
+
+	assert.Equal(t, []string{"github", "monalisa"}, sortedOwners(grouped))
+	assert.Equal(t, []int32{1, 3}, projectNumbers(grouped["github"]))
+	assert.Equal(t, []int32{2}, projectNumbers(grouped["monalisa"]))
+}
+
+func TestGroupOrgResultsByOwner(t *testing.T) {
+	results := []orgResult{
+		{Owner: "github", Project: queries.Project{Number: 1}},
+		{Owner: "monalisa", Skipped: true},
+		{Owner: "github", Error: "boom"},
+	}
+
+	grouped := groupOrgResultsByOwner(results)
+
+	assert.Equal(t, []string{"github", "monalisa"}, sortedOwners(grouped))
+	assert.Len(t, grouped["github"], 2)
+	assert.Len(t, grouped["monalisa"], 1)
+}
+
+func TestGroupSearchMatchesByOwner(t *testing.T) {
+	matches := []searchMatch{
+		{Project: projectOwnedBy("github", 1)},
+		{Project: projectOwnedBy("monalisa", 2)},
+	}
+
+	grouped := groupSearchMatchesByOwner(matches)
+
+	assert.Equal(t, []string{"github", "monalisa"}, sortedOwners(grouped))
+}
+
+func projectOwnedBy(login string, number int32) queries.Project {
+	p := queries.Project{Number: number}
+	p.Owner.TypeName = "Organization"
+	p.Owner.Organization.Login = login
+	return p
+}
+
+func projectNumbers(projects []queries.Project) []int32 {
+	numbers := make([]int32, len(projects))
+	for i, p := range projects {
+		numbers[i] = p.Number
+	}
+	return numbers
+}
+
+func TestRunAllOrgs_GroupByOwner(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"viewer": map[string]interface{}{
+					"login": "monalisa",
+					"id":    "viewer ID",
+					"organizations": map[string]interface{}{
+						"pageInfo": map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"login": "github", "viewerCanCreateProjects": true, "id": "github org ID"},
+							map[string]interface{}{"login": "cli", "viewerCanCreateProjects": true, "id": "cli org ID"},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectsV2": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"title": "Roadmap", "id": "github project ID", "number": 1},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "github project ID", "number": 1, "title": "Roadmap"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "cli",
+					"projectsV2": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"title": "Roadmap", "id": "cli project ID", "number": 2},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "cli project ID", "number": 2, "title": "Roadmap"},
+				},
+			},
+		})
+
+	client := queries.NewTestClient()
+	ios, _, stdout, _ := iostreams.Test()
+	config := templateConfig{
+		opts: templateOpts{
+			allOrgs:      true,
+			title:        "Roadmap",
+			yes:          true,
+			groupByOwner: true,
+			exporter:     cmdutil.NewJSONExporter(),
+		},
+		client: client,
+		io:     ios,
+	}
+
+	err := runAllOrgs(config)
+	assert.NoError(t, err)
+
+	var grouped map[string][]orgResult
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &grouped))
+
+	assert.Equal(t, []string{"cli", "github"}, sortedOwners(grouped))
+	assert.Equal(t, "cli", grouped["cli"][0].Owner)
+	assert.Equal(t, int32(2), grouped["cli"][0].Project.Number)
+	assert.Equal(t, "github", grouped["github"][0].Owner)
+	assert.Equal(t, int32(1), grouped["github"][0].Project.Number)
+}
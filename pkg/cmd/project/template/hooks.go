@@ -0,0 +1,64 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// hookEnvironment builds the environment variables passed to --pre-hook and
+// --post-hook on top of the process's own environment: GH_PROJECT_NUMBER,
+// GH_PROJECT_ID, GH_PROJECT_OWNER, and GH_TEMPLATE_ACTION ("mark" or
+// "unmark"). owner may be nil (e.g. for a --search match, which has no
+// single owner), in which case GH_PROJECT_OWNER falls back to --owner,
+// which is itself often empty for that same reason.
+func hookEnvironment(config templateConfig, owner *queries.Owner) map[string]string {
+	action := "mark"
+	if config.opts.undo {
+		action = "unmark"
+	}
+
+	ownerLogin := config.opts.owner
+	if owner != nil {
+		ownerLogin = owner.Login
+	}
+
+	return map[string]string{
+		"GH_PROJECT_NUMBER":  strconv.Itoa(int(config.opts.number)),
+		"GH_PROJECT_ID":      config.opts.projectID,
+		"GH_PROJECT_OWNER":   ownerLogin,
+		"GH_TEMPLATE_ACTION": action,
+	}
+}
+
+// runHook runs hookCmd through the platform shell, with env merged into the
+// hook's environment on top of the process's own. hookCmd is run as-is,
+// the same way a user's own shell would run it, so it can use pipes,
+// redirection, and other shell syntax; this also means --pre-hook and
+// --post-hook should only ever be set to a trusted command, never to
+// unsanitized input, since it runs with the same privileges as gh itself.
+func runHook(config templateConfig, hookCmd string, env map[string]string) error {
+	cmd := shellCommand(hookCmd)
+	cmd.Stdout = config.io.Out
+	cmd.Stderr = config.io.ErrOut
+
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	return cmd.Run()
+}
+
+// shellCommand wraps raw in the platform's shell, the same way `git` runs
+// hook scripts: "sh -c" on Unix-likes, "cmd /C" on Windows.
+func shellCommand(raw string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", raw)
+	}
+	return exec.Command("sh", "-c", raw)
+}
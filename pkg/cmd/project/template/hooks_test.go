@@ -0,0 +1,216 @@
+package template
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/internal/gh"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRunTemplate_Hooks(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+	mockRateLimitOK()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query UserOrgOwner.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"id": "an ID"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{
+					"type": "NOT_FOUND",
+					"path": []string{"user"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProject.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID", "number": 1},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	preFile := filepath.Join(dir, "pre.txt")
+	postFile := filepath.Join(dir, "post.txt")
+
+	ios, _, stdout, _ := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	cfg := templateConfig{
+		opts: templateOpts{
+			owner:    "github",
+			number:   1,
+			preHook:  `printf '%s %s %s %s' "$GH_TEMPLATE_ACTION" "$GH_PROJECT_NUMBER" "$GH_PROJECT_OWNER" "$GH_PROJECT_ID" > ` + preFile,
+			postHook: `printf '%s %s' "$GH_TEMPLATE_ACTION" "$GH_PROJECT_NUMBER" > ` + postFile,
+		},
+		client: queries.NewTestClient(),
+		httpClient: func() (*http.Client, error) {
+			return http.DefaultClient, nil
+		},
+		cfg: func() (gh.Config, error) {
+			return config.NewBlankConfig(), nil
+		},
+		io: ios,
+	}
+
+	err := runTemplate(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "Marked project 1 as a template.\n", stdout.String())
+
+	preContents, err := os.ReadFile(preFile)
+	assert.NoError(t, err)
+	// --pre-hook runs before the project ID is known to be the mutated
+	// one, but GH_PROJECT_ID is already set from project lookup/--id.
+	assert.Equal(t, "mark 1 github project ID", string(preContents))
+
+	postContents, err := os.ReadFile(postFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "mark 1", string(postContents))
+}
+
+func TestRunTemplate_PreHookFailureAborts(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+	mockRateLimitOK()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query UserOrgOwner.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"id": "an ID"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{
+					"type": "NOT_FOUND",
+					"path": []string{"user"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProject.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID"},
+				},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	cfg := templateConfig{
+		opts: templateOpts{
+			owner:   "github",
+			number:  1,
+			preHook: "exit 1",
+		},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runTemplate(cfg)
+	assert.ErrorContains(t, err, "--pre-hook failed, aborting")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestRunTemplate_PostHookFailureWarns(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+	mockRateLimitOK()
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query UserOrgOwner.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"id": "an ID"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{
+					"type": "NOT_FOUND",
+					"path": []string{"user"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProject.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID", "number": 1},
+				},
+			},
+		})
+
+	ios, _, stdout, stderr := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	cfg := templateConfig{
+		opts: templateOpts{
+			owner:    "github",
+			number:   1,
+			postHook: "exit 1",
+		},
+		client: queries.NewTestClient(),
+		httpClient: func() (*http.Client, error) {
+			return http.DefaultClient, nil
+		},
+		cfg: func() (gh.Config, error) {
+			return config.NewBlankConfig(), nil
+		},
+		io: ios,
+	}
+
+	err := runTemplate(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "Marked project 1 as a template.\n", stdout.String())
+	assert.Contains(t, stderr.String(), "--post-hook failed")
+}
@@ -0,0 +1,253 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/tableprinter"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/client"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+)
+
+type importOpts struct {
+	owner      string
+	number     int32
+	fromOwner  string
+	fromNumber int32
+	onConflict string
+	exporter   cmdutil.Exporter
+}
+
+type importConfig struct {
+	client *queries.Client
+	opts   importOpts
+	io     *iostreams.IOStreams
+}
+
+type createProjectV2FieldMutation struct {
+	CreateProjectV2Field struct {
+		Field queries.ProjectField `graphql:"projectV2Field"`
+	} `graphql:"createProjectV2Field(input:$input)"`
+}
+
+type deleteProjectV2FieldMutation struct {
+	DeleteProjectV2Field struct {
+		Field queries.ProjectField `graphql:"projectV2Field"`
+	} `graphql:"deleteProjectV2Field(input:$input)"`
+}
+
+// fieldImportResult is one line of `template import`'s report: what
+// happened to a single field from the template while recreating its
+// structure on the target project.
+type fieldImportResult struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// importableFieldDataTypes are the ProjectV2FieldType values
+// CreateProjectV2FieldInput accepts. Every other field is either a
+// built-in field GitHub attaches to every project (e.g. "ASSIGNEES",
+// "TITLE") or an iteration field, neither of which can be created through
+// this mutation, so those fields are always skipped regardless of
+// --on-conflict.
+var importableFieldDataTypes = map[string]bool{
+	"TEXT":          true,
+	"SINGLE_SELECT": true,
+	"NUMBER":        true,
+	"DATE":          true,
+}
+
+// newCmdImport returns the `gh project template import` subcommand, which
+// recreates a template project's field structure on a target project:
+// every field the template has that the target doesn't gets created, and
+// --on-conflict controls what happens to a field the target already has
+// under the same name.
+func newCmdImport(f *cmdutil.Factory, runF func(config importConfig) error) *cobra.Command {
+	opts := importOpts{}
+	importCmd := &cobra.Command{
+		Use:   "import <number>",
+		Short: "Recreate a template project's fields on another project",
+		Example: heredoc.Doc(`
+			# copy every field from template project 1 onto project 5, skipping
+			# any field project 5 already has under the same name
+			gh project template import 5 --owner github --from 1 --from-owner github
+
+			# fail instead of skipping if any field names collide
+			gh project template import 5 --owner github --from 1 --from-owner github --on-conflict error
+
+			# replace project 5's conflicting fields with the template's versions
+			gh project template import 5 --owner github --from 1 --from-owner github --on-conflict overwrite
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.owner == "" {
+				return cmdutil.FlagErrorf("--owner is required")
+			}
+			if opts.fromOwner == "" {
+				return cmdutil.FlagErrorf("--from-owner is required")
+			}
+			if opts.fromNumber == 0 {
+				return cmdutil.FlagErrorf("--from is required")
+			}
+
+			number, err := parseProjectNumber(args[0])
+			if err != nil {
+				return cmdutil.FlagErrorf("%s", err)
+			}
+			opts.number = number
+
+			client, err := client.New(f)
+			if err != nil {
+				return err
+			}
+
+			config := importConfig{
+				client: client,
+				opts:   opts,
+				io:     f.IOStreams,
+			}
+
+			if runF != nil {
+				return runF(config)
+			}
+			return runImport(config)
+		},
+	}
+
+	importCmd.Flags().StringVar(&opts.owner, "owner", "", "Login of the target project's owner. Use \"@me\" for the current user.")
+	importCmd.Flags().StringVar(&opts.fromOwner, "from-owner", "", "Login of the template project's owner. Use \"@me\" for the current user.")
+	importCmd.Flags().Int32Var(&opts.fromNumber, "from", 0, "Number of the template project to copy fields from.")
+	cmdutil.StringEnumFlag(importCmd, &opts.onConflict, "on-conflict", "", "skip", []string{"skip", "error", "overwrite"}, "How to handle a field the target project already has under the same name")
+	cmdutil.AddFormatFlags(importCmd, &opts.exporter)
+
+	return importCmd
+}
+
+// runImport fetches both projects' fields, then for each field present in
+// the template project either creates it on the target (no name collision)
+// or applies --on-conflict's policy: skip it with a warning (the default),
+// fail the whole import, or delete the target's existing field and
+// recreate it with the template's definition.
+func runImport(config importConfig) error {
+	canPrompt := config.io.CanPrompt()
+
+	fromOwner, err := config.client.NewOwner(canPrompt, config.opts.fromOwner)
+	if err != nil {
+		return err
+	}
+	fromProject, err := config.client.ProjectFields(fromOwner, config.opts.fromNumber, 0)
+	if err != nil {
+		return fmt.Errorf("could not read template project's fields: %w", err)
+	}
+
+	owner, err := config.client.NewOwner(canPrompt, config.opts.owner)
+	if err != nil {
+		return err
+	}
+	targetProject, err := config.client.ProjectFields(owner, config.opts.number, 0)
+	if err != nil {
+		return fmt.Errorf("could not read target project's fields: %w", err)
+	}
+
+	existing := make(map[string]queries.ProjectField, len(targetProject.Fields.Nodes))
+	for _, f := range targetProject.Fields.Nodes {
+		existing[f.Name()] = f
+	}
+
+	var results []fieldImportResult
+	for _, field := range fromProject.Fields.Nodes {
+		if !importableFieldDataTypes[field.DataType()] {
+			results = append(results, fieldImportResult{Name: field.Name(), Action: "skipped", Reason: fmt.Sprintf("%s fields cannot be recreated by this command", field.DataType())})
+			continue
+		}
+
+		conflict, hasConflict := existing[field.Name()]
+		if !hasConflict {
+			if err := createImportedField(config, targetProject.ID, field); err != nil {
+				return fmt.Errorf("could not create field %q: %w", field.Name(), err)
+			}
+			results = append(results, fieldImportResult{Name: field.Name(), Action: "created"})
+			continue
+		}
+
+		switch config.opts.onConflict {
+		case "error":
+			return fmt.Errorf("field %q already exists on the target project", field.Name())
+		case "overwrite":
+			if err := config.client.Mutate("DeleteField", &deleteProjectV2FieldMutation{}, map[string]interface{}{
+				"input": githubv4.DeleteProjectV2FieldInput{FieldID: githubv4.ID(conflict.ID())},
+			}); err != nil {
+				return fmt.Errorf("could not delete existing field %q: %w", field.Name(), err)
+			}
+			if err := createImportedField(config, targetProject.ID, field); err != nil {
+				return fmt.Errorf("could not recreate field %q: %w", field.Name(), err)
+			}
+			results = append(results, fieldImportResult{Name: field.Name(), Action: "overwritten"})
+		default:
+			if err := printImportSkipWarning(config, field.Name()); err != nil {
+				return err
+			}
+			results = append(results, fieldImportResult{Name: field.Name(), Action: "skipped", Reason: "field already exists on the target project"})
+		}
+	}
+
+	if config.opts.exporter != nil {
+		return config.opts.exporter.Write(config.io, results)
+	}
+
+	return printImportResults(config, results)
+}
+
+// createImportedField creates a field on projectID matching field's name,
+// data type, and (for a single-select field) options, including each
+// option's color and description, not just its name.
+func createImportedField(config importConfig, projectID string, field queries.ProjectField) error {
+	input := githubv4.CreateProjectV2FieldInput{
+		ProjectID: githubv4.ID(projectID),
+		DataType:  githubv4.ProjectV2CustomFieldType(field.DataType()),
+		Name:      githubv4.String(field.Name()),
+	}
+
+	if opts := field.Options(); len(opts) != 0 {
+		singleSelectOptions := make([]githubv4.ProjectV2SingleSelectFieldOptionInput, 0, len(opts))
+		for _, o := range opts {
+			singleSelectOptions = append(singleSelectOptions, githubv4.ProjectV2SingleSelectFieldOptionInput{
+				Name:        githubv4.String(o.Name),
+				Color:       githubv4.ProjectV2SingleSelectFieldOptionColor(o.Color),
+				Description: githubv4.String(o.Description),
+			})
+		}
+		input.SingleSelectOptions = &singleSelectOptions
+	}
+
+	return config.client.Mutate("CreateField", &createProjectV2FieldMutation{}, map[string]interface{}{"input": input})
+}
+
+func printImportSkipWarning(config importConfig, name string) error {
+	if !config.io.IsStderrTTY() {
+		return nil
+	}
+	cs := config.io.ColorScheme()
+	_, err := fmt.Fprintf(config.io.ErrOut, "%s skipping field %q: already exists on the target project\n", cs.WarningIcon(), name)
+	return err
+}
+
+func printImportResults(config importConfig, results []fieldImportResult) error {
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	tp := tableprinter.New(config.io, tableprinter.WithHeader("Field", "Action", "Reason"))
+	for _, r := range results {
+		tp.AddField(r.Name)
+		tp.AddField(r.Action)
+		tp.AddField(r.Reason)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
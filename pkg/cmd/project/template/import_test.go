@@ -0,0 +1,292 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/google/shlex"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestNewCmdImport(t *testing.T) {
+	tests := []struct {
+		name        string
+		cli         string
+		wants       importOpts
+		wantsErr    bool
+		wantsErrMsg string
+	}{
+		{
+			name:        "missing owner",
+			cli:         "5 --from 1 --from-owner github",
+			wantsErr:    true,
+			wantsErrMsg: "--owner is required",
+		},
+		{
+			name:        "missing from-owner",
+			cli:         "5 --owner github --from 1",
+			wantsErr:    true,
+			wantsErrMsg: "--from-owner is required",
+		},
+		{
+			name:        "missing from",
+			cli:         "5 --owner github --from-owner github",
+			wantsErr:    true,
+			wantsErrMsg: "--from is required",
+		},
+		{
+			name:        "invalid on-conflict",
+			cli:         "5 --owner github --from 1 --from-owner github --on-conflict bogus",
+			wantsErr:    true,
+			wantsErrMsg: `valid values are {skip|error|overwrite}`,
+		},
+		{
+			name: "defaults",
+			cli:  "5 --owner github --from 1 --from-owner github",
+			wants: importOpts{
+				owner:      "github",
+				number:     5,
+				fromOwner:  "github",
+				fromNumber: 1,
+				onConflict: "skip",
+			},
+		},
+		{
+			name: "overwrite",
+			cli:  "5 --owner github --from 1 --from-owner github --on-conflict overwrite",
+			wants: importOpts{
+				owner:      "github",
+				number:     5,
+				fromOwner:  "github",
+				fromNumber: 1,
+				onConflict: "overwrite",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, _, _, _ := iostreams.Test()
+			f := &cmdutil.Factory{
+				IOStreams: ios,
+			}
+
+			argv, err := shlex.Split(tt.cli)
+			assert.NoError(t, err)
+
+			var gotOpts importOpts
+			cmd := newCmdImport(f, func(config importConfig) error {
+				gotOpts = config.opts
+				return nil
+			})
+
+			cmd.SetArgs(argv)
+			_, err = cmd.ExecuteC()
+			if tt.wantsErr {
+				assert.ErrorContains(t, err, tt.wantsErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wants.owner, gotOpts.owner)
+			assert.Equal(t, tt.wants.number, gotOpts.number)
+			assert.Equal(t, tt.wants.fromOwner, gotOpts.fromOwner)
+			assert.Equal(t, tt.wants.fromNumber, gotOpts.fromNumber)
+			assert.Equal(t, tt.wants.onConflict, gotOpts.onConflict)
+		})
+	}
+}
+
+// mockOwnerAndFields queues the owner-resolution and ProjectFields mocks for
+// one org owner/project/field-set combination, assuming OrgOwner (set via
+// client.SetAssumedOwnerType) so the test doesn't also have to mock the
+// user-or-org probing query.
+func mockOwnerAndFields(login, projectID string, number int32, nodes []interface{}) {
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		JSON(map[string]interface{}{
+			"query":     "query AssumedOrgOwner.*",
+			"variables": map[string]interface{}{"login": login},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"id": login + " ID"},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		JSON(map[string]interface{}{
+			"query": "query OrgProjectWithFields.*",
+			"variables": map[string]interface{}{
+				"login":       login,
+				"number":      number,
+				"firstItems":  queries.LimitMax,
+				"afterItems":  nil,
+				"firstFields": queries.LimitDefault,
+				"afterFields": nil,
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"id":     projectID,
+						"fields": map[string]interface{}{"nodes": nodes},
+					},
+				},
+			},
+		})
+}
+
+func TestRunImport(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	mockOwnerAndFields("template-org", "template-project", 1, []interface{}{
+		map[string]interface{}{"__typename": "ProjectV2SingleSelectField", "name": "Status", "id": "template-status-id", "dataType": "SINGLE_SELECT", "options": []interface{}{
+			map[string]interface{}{"id": "opt1", "name": "Todo", "color": "YELLOW", "description": "Not started"},
+		}},
+		map[string]interface{}{"__typename": "ProjectV2Field", "name": "Points", "id": "template-points-id", "dataType": "NUMBER"},
+		map[string]interface{}{"__typename": "ProjectV2IterationField", "name": "Sprint", "id": "template-sprint-id", "dataType": "ITERATION"},
+	})
+
+	mockOwnerAndFields("target-org", "target-project", 5, []interface{}{
+		map[string]interface{}{"__typename": "ProjectV2SingleSelectField", "name": "Status", "id": "existing-status-id", "dataType": "SINGLE_SELECT"},
+		map[string]interface{}{"__typename": "ProjectV2Field", "name": "Notes", "id": "existing-notes-id", "dataType": "TEXT"},
+	})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation DeleteField.*","variables":{"input":{"fieldId":"existing-status-id"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"deleteProjectV2Field": map[string]interface{}{
+					"projectV2Field": map[string]interface{}{"__typename": "ProjectV2SingleSelectField", "name": "Status", "id": "existing-status-id"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation CreateField.*","variables":{"input":{"projectId":"target-project","dataType":"SINGLE_SELECT","name":"Status","singleSelectOptions":\[{"name":"Todo","color":"YELLOW","description":"Not started"}\]}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"createProjectV2Field": map[string]interface{}{
+					"projectV2Field": map[string]interface{}{"__typename": "ProjectV2SingleSelectField", "name": "Status", "id": "new-status-id"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation CreateField.*","variables":{"input":{"projectId":"target-project","dataType":"NUMBER","name":"Points"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"createProjectV2Field": map[string]interface{}{
+					"projectV2Field": map[string]interface{}{"__typename": "ProjectV2Field", "name": "Points", "id": "new-points-id"},
+				},
+			},
+		})
+
+	client := queries.NewTestClient()
+	client.SetAssumedOwnerType(queries.OrgOwner)
+
+	ios, _, stdout, _ := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	config := importConfig{
+		opts: importOpts{
+			owner:      "target-org",
+			number:     5,
+			fromOwner:  "template-org",
+			fromNumber: 1,
+			onConflict: "overwrite",
+		},
+		client: client,
+		io:     ios,
+	}
+
+	err := runImport(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "Status")
+	assert.Contains(t, stdout.String(), "overwritten")
+	assert.Contains(t, stdout.String(), "Points")
+	assert.Contains(t, stdout.String(), "created")
+	assert.Contains(t, stdout.String(), "Sprint")
+	assert.Contains(t, stdout.String(), "skipped")
+	assert.Contains(t, stdout.String(), "ITERATION fields cannot be recreated by this command")
+}
+
+func TestRunImport_SkipsConflictByDefault(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	mockOwnerAndFields("template-org", "template-project", 1, []interface{}{
+		map[string]interface{}{"__typename": "ProjectV2Field", "name": "Notes", "id": "template-notes-id", "dataType": "TEXT"},
+	})
+	mockOwnerAndFields("target-org", "target-project", 5, []interface{}{
+		map[string]interface{}{"__typename": "ProjectV2Field", "name": "Notes", "id": "existing-notes-id", "dataType": "TEXT"},
+	})
+
+	client := queries.NewTestClient()
+	client.SetAssumedOwnerType(queries.OrgOwner)
+
+	ios, _, stdout, _ := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	config := importConfig{
+		opts: importOpts{
+			owner:      "target-org",
+			number:     5,
+			fromOwner:  "template-org",
+			fromNumber: 1,
+			onConflict: "skip",
+		},
+		client: client,
+		io:     ios,
+	}
+
+	err := runImport(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "Notes")
+	assert.Contains(t, stdout.String(), "skipped")
+	assert.Contains(t, stdout.String(), "already exists on the target project")
+}
+
+func TestRunImport_ErrorsOnConflict(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	mockOwnerAndFields("template-org", "template-project", 1, []interface{}{
+		map[string]interface{}{"__typename": "ProjectV2Field", "name": "Notes", "id": "template-notes-id", "dataType": "TEXT"},
+	})
+	mockOwnerAndFields("target-org", "target-project", 5, []interface{}{
+		map[string]interface{}{"__typename": "ProjectV2Field", "name": "Notes", "id": "existing-notes-id", "dataType": "TEXT"},
+	})
+
+	client := queries.NewTestClient()
+	client.SetAssumedOwnerType(queries.OrgOwner)
+
+	ios, _, _, _ := iostreams.Test()
+	config := importConfig{
+		opts: importOpts{
+			owner:      "target-org",
+			number:     5,
+			fromOwner:  "template-org",
+			fromNumber: 1,
+			onConflict: "error",
+		},
+		client: client,
+		io:     ios,
+	}
+
+	err := runImport(config)
+	assert.EqualError(t, err, `field "Notes" already exists on the target project`)
+}
@@ -0,0 +1,51 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+)
+
+// linkProjectToRepo resolves config.opts.linkRepo ("owner/name") and links
+// project to it, for --link-repo. It is only called after the project has
+// already been marked as a template, so a failure here is reported to the
+// caller as a partial-success error rather than rolling back the mark.
+func linkProjectToRepo(config templateConfig, project queries.Project) error {
+	owner, name, ok := strings.Cut(config.opts.linkRepo, "/")
+	if !ok {
+		return fmt.Errorf("expected the \"OWNER/REPO\" format, got %q", config.opts.linkRepo)
+	}
+
+	httpClient, err := config.httpClient()
+	if err != nil {
+		return err
+	}
+	c := api.NewClientFromHTTP(httpClient)
+
+	cfg, err := config.cfg()
+	if err != nil {
+		return err
+	}
+	host, _ := cfg.Authentication().DefaultHost()
+
+	repo, err := api.GitHubRepo(c, ghrepo.NewWithHost(owner, name, host))
+	if err != nil {
+		return err
+	}
+
+	return config.client.LinkProjectToRepository(project.ID, repo.ID)
+}
+
+// printLinkResult reports a successful --link-repo on a TTY, mirroring
+// `gh project link`'s own confirmation message.
+func printLinkResult(config templateConfig, project queries.Project) error {
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(config.io.Out, "Linked '%s' to project #%d '%s'\n", config.opts.linkRepo, project.Number, project.Title)
+	return err
+}
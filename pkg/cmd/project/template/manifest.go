@@ -0,0 +1,266 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+)
+
+// manifestEntry describes one project to mark or unmark as a template when
+// processing a --from-file batch. It mirrors the fields the single-project
+// form accepts as flags.
+type manifestEntry struct {
+	Owner  string `json:"owner"`
+	Number int32  `json:"number"`
+	Undo   bool   `json:"undo"`
+}
+
+// manifestValidationError aggregates every malformed entry found in a
+// manifest, so a user can fix all of them in one pass instead of rerunning
+// the command after each individual fix.
+type manifestValidationError struct {
+	errs []string
+}
+
+func (e *manifestValidationError) Error() string {
+	return strings.Join(e.errs, "\n")
+}
+
+// loadManifest reads and validates the JSON array of manifestEntry values at
+// path (use "-" to read from stdin). All entries are validated before any of
+// them are returned, so a caller only has to run the command once to see
+// every problem in a large manifest.
+func loadManifest(path string, config templateConfig) ([]manifestEntry, error) {
+	data, err := cmdutil.ReadFile(path, config.io.In)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []manifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("could not parse manifest: %w", err)
+	}
+
+	var errs []string
+	firstSeen := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		if entry.Owner == "" {
+			errs = append(errs, fmt.Sprintf("entry [%d]: missing 'owner'", i))
+		}
+		if entry.Number == 0 {
+			errs = append(errs, fmt.Sprintf("entry [%d]: missing or zero 'number'", i))
+		}
+		if entry.Owner == "" || entry.Number == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s#%d", entry.Owner, entry.Number)
+		if first, ok := firstSeen[key]; ok {
+			errs = append(errs, fmt.Sprintf("entry [%d]: duplicate of entry [%d] (owner %q, number %d)", i, first, entry.Owner, entry.Number))
+			continue
+		}
+		firstSeen[key] = i
+	}
+	if len(errs) > 0 {
+		return nil, &manifestValidationError{errs: errs}
+	}
+
+	return entries, nil
+}
+
+// printManifestValid reports that path's manifest parsed and validated
+// cleanly, for a --validate-only run that found nothing to report. It is a
+// CI-lint-friendly success message: nothing is marked or unmarked, and no
+// network calls are made either way.
+func printManifestValid(config templateConfig, path string, entries []manifestEntry) error {
+	if config.opts.exporter != nil {
+		return config.opts.exporter.Write(config.io, map[string]interface{}{
+			"valid":   true,
+			"entries": len(entries),
+		})
+	}
+
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(config.io.Out, "%s is valid: %d entries\n", path, len(entries))
+	return err
+}
+
+// runManifest processes every entry in a --from-file manifest, accumulating
+// a single batchSummary across the whole batch rather than printing one per
+// entry. A single entry failing does not stop the rest of the batch from
+// being processed. Entries run up to --concurrency at a time overall, and up
+// to --owner-concurrency at a time for any one owner (see
+// runConcurrentEntries); warnings and the summary are still produced in
+// entry order, after every entry has finished. With --jsonl, per-entry
+// warnings and the human-readable summary are replaced by a JSON Lines
+// stream: one line per entry as it is tallied, then a final
+// ("type":"summary") line.
+func runManifest(config templateConfig) error {
+	entries, err := loadManifest(config.opts.fromFile, config)
+	if err != nil {
+		return err
+	}
+
+	if config.opts.validateOnly {
+		return printManifestValid(config, config.opts.fromFile, entries)
+	}
+
+	if err := checkMaxAffected(config, len(entries)); err != nil {
+		return err
+	}
+
+	return runEntries(config, entries)
+}
+
+// runEntries processes entries the same way runManifest does, sharing its
+// concurrency, per-entry warning, and summary behavior. It backs both
+// --from-file, whose entries come from loadManifest, and --range, whose
+// entries are generated from the parsed number sequence instead. A TTY
+// shows a progress indicator that updates as entries complete.
+func runEntries(config templateConfig, entries []manifestEntry) error {
+	start := time.Now()
+
+	sharedOwner, err := resolveSharedOwner(config, entries)
+	if err != nil {
+		return err
+	}
+
+	progress := newBatchProgress(config, len(entries))
+	progress.start()
+
+	outcomes := runConcurrentEntries(config.opts.concurrency, config.opts.ownerConcurrency, entries, func(entry manifestEntry) entryOutcome {
+		entryConfig := config
+		entryConfig.opts.owner = entry.Owner
+		entryConfig.opts.number = entry.Number
+		entryConfig.opts.undo = entry.Undo
+
+		mutated, retries, noop, err := processManifestEntry(entryConfig, sharedOwner)
+		return entryOutcome{mutated: mutated, retries: retries, noop: noop, err: err}
+	}, progress.increment)
+
+	progress.stop()
+
+	summary := batchSummary{}
+	var results []queries.Project
+	var totalRetries int
+
+	for i, entry := range entries {
+		outcome := outcomes[i]
+		totalRetries += outcome.retries
+
+		if config.opts.jsonl {
+			if err := writeJSONLItem(config, entry, outcome); err != nil {
+				return err
+			}
+		}
+
+		if outcome.err != nil {
+			summary.Failed++
+			if !config.opts.jsonl {
+				if warnErr := printManifestEntryWarning(config, i, outcome.err); warnErr != nil {
+					return warnErr
+				}
+			}
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case outcome.noop:
+			summary.Noop++
+		case entry.Undo:
+			summary.Unmarked++
+		default:
+			summary.Marked++
+		}
+		results = append(results, outcome.mutated)
+	}
+
+	if config.opts.jsonl {
+		if err := writeJSONLSummary(config, summary); err != nil {
+			return err
+		}
+	} else if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+	if err := writeMetricsFile(config.opts.metricsFile, summary, totalRetries, time.Since(start)); err != nil {
+		return err
+	}
+
+	var output interface{} = results
+	if config.opts.groupByOwner {
+		grouped := groupProjectsByOwner(results)
+		if err := printGroupedProjects(config, grouped); err != nil {
+			return err
+		}
+		output = grouped
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, output); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, output)
+}
+
+// resolveSharedOwner resolves the owner once for a batch whose entries all
+// name the same owner (e.g. every --range entry, which shares
+// config.opts.owner by construction), so the per-entry processing below can
+// reuse its node ID instead of re-resolving it once per entry. Returns nil
+// when the entries don't all share one owner, which tells processManifestEntry
+// to fall back to its usual per-entry resolution.
+func resolveSharedOwner(config templateConfig, entries []manifestEntry) (*queries.Owner, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	login := entries[0].Owner
+	for _, entry := range entries[1:] {
+		if entry.Owner != login {
+			return nil, nil
+		}
+	}
+
+	entryConfig := config
+	entryConfig.opts.owner = login
+	return resolveOwner(entryConfig)
+}
+
+func processManifestEntry(config templateConfig, sharedOwner *queries.Owner) (queries.Project, int, bool, error) {
+	owner := sharedOwner
+	if owner == nil {
+		var err error
+		owner, err = resolveOwner(config)
+		if err != nil {
+			return queries.Project{}, 0, false, err
+		}
+	}
+
+	project, err := config.client.NewProject(false, owner, config.opts.number, false)
+	if err != nil {
+		return queries.Project{}, 0, false, err
+	}
+	config.opts.projectID = project.ID
+
+	return applyMutation(config, owner)
+}
+
+func printManifestEntryWarning(config templateConfig, index int, err error) error {
+	if config.opts.quietErrors || config.opts.quiet {
+		return nil
+	}
+	if werr := printWarningAnnotation(config, fmt.Sprintf("entry [%d]: %s", index, err)); werr != nil {
+		return werr
+	}
+	cs := config.io.ColorScheme()
+	_, werr := fmt.Fprintf(config.io.ErrOut, "%s entry [%d]: %s\n", cs.WarningIcon(), index, err)
+	return werr
+}
@@ -0,0 +1,42 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// writeMetricsFile renders summary, the total verification retries performed,
+// and the run's wall-clock duration as Prometheus textfile-format metrics and
+// writes them to path, for consumption by node_exporter's textfile
+// collector. It is a write-once call made after a run completes, not a
+// long-lived exporter. It is a no-op when path is empty.
+func writeMetricsFile(path string, summary batchSummary, retries int, duration time.Duration) error {
+	if path == "" {
+		return nil
+	}
+
+	content := fmt.Sprintf(
+		"# HELP gh_project_template_marked_total Projects marked as a template.\n"+
+			"# TYPE gh_project_template_marked_total counter\n"+
+			"gh_project_template_marked_total %d\n"+
+			"# HELP gh_project_template_unmarked_total Projects unmarked as a template.\n"+
+			"# TYPE gh_project_template_unmarked_total counter\n"+
+			"gh_project_template_unmarked_total %d\n"+
+			"# HELP gh_project_template_failed_total Projects that failed to process.\n"+
+			"# TYPE gh_project_template_failed_total counter\n"+
+			"gh_project_template_failed_total %d\n"+
+			"# HELP gh_project_template_verify_retries_total Verification retries performed across the run.\n"+
+			"# TYPE gh_project_template_verify_retries_total counter\n"+
+			"gh_project_template_verify_retries_total %d\n"+
+			"# HELP gh_project_template_duration_seconds Wall-clock duration of the run.\n"+
+			"# TYPE gh_project_template_duration_seconds gauge\n"+
+			"gh_project_template_duration_seconds %f\n",
+		summary.Marked, summary.Unmarked, summary.Failed, retries, duration.Seconds(),
+	)
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("could not write metrics file: %w", err)
+	}
+	return nil
+}
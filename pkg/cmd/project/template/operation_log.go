@@ -0,0 +1,46 @@
+package template
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// operationLogEntry is one JSON line written to --operation-log: a single
+// mark/unmark mutation, recorded with enough information (owner, number,
+// and which direction was applied) for --undo-log to later replay its
+// inverse.
+type operationLogEntry struct {
+	Timestamp string `json:"timestamp"`
+	Owner     string `json:"owner"`
+	Number    int32  `json:"number"`
+	Undo      bool   `json:"undo"`
+}
+
+// appendOperationLog appends an operationLogEntry for one mark/unmark
+// mutation to config.opts.operationLog.
+func appendOperationLog(config templateConfig, owner string, number int32, undo bool) error {
+	f, err := os.OpenFile(config.opts.operationLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(operationLogEntry{
+		Timestamp: config.opts.now().UTC().Format(time.RFC3339),
+		Owner:     owner,
+		Number:    number,
+		Undo:      undo,
+	})
+}
+
+// validateOperationLogPath confirms path can be opened for appending, so
+// --operation-log fails fast on a bad path rather than partway through a
+// run whose mutations it would otherwise lose.
+func validateOperationLogPath(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
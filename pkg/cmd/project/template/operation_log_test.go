@@ -0,0 +1,44 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendOperationLog(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/ops.jsonl"
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts: templateOpts{operationLog: path, now: func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }},
+		io:   ios,
+	}
+
+	assert.NoError(t, appendOperationLog(config, "github", 1, false))
+	assert.NoError(t, appendOperationLog(config, "github", 2, true))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var first, second operationLogEntry
+	assert.NoError(t, dec.Decode(&first))
+	assert.NoError(t, dec.Decode(&second))
+
+	assert.Equal(t, operationLogEntry{Timestamp: "2024-01-02T03:04:05Z", Owner: "github", Number: 1, Undo: false}, first)
+	assert.Equal(t, operationLogEntry{Timestamp: "2024-01-02T03:04:05Z", Owner: "github", Number: 2, Undo: true}, second)
+}
+
+func TestValidateOperationLogPath(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, validateOperationLogPath(dir+"/ops.jsonl"))
+	assert.Error(t, validateOperationLogPath(dir+"/missing-dir/ops.jsonl"))
+}
@@ -0,0 +1,79 @@
+package template
+
+import (
+	"encoding/json"
+	"io"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cli/cli/v2/internal/build"
+)
+
+// jsonIndent returns the indent string for a pretty-printed JSON encoder
+// given an --indent width in spaces.
+func jsonIndent(width int) string {
+	return strings.Repeat(" ", width)
+}
+
+// outputMeta records which binary produced a --output record, so a dump can
+// still be traced back to its source months later. It is deliberately
+// limited to build-time facts (version, commit): no timestamps or other
+// run-specific values, so the same binary always produces an identical meta
+// block regardless of when or how many times it runs.
+type outputMeta struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+}
+
+// outputEnvelope wraps a --output payload with outputMeta, for --include-meta.
+type outputEnvelope struct {
+	Meta outputMeta  `json:"meta"`
+	Data interface{} `json:"data"`
+}
+
+// buildOutputMeta reads the CLI version and git commit that produced this
+// binary, for --include-meta. The version comes from internal/build.Version,
+// the same value gh version prints; the commit comes from the vcs.revision
+// setting Go's toolchain embeds in the binary since Go 1.18, which is absent
+// for binaries built without a .git directory present (e.g. from a source
+// tarball), in which case Commit is left empty.
+func buildOutputMeta() outputMeta {
+	meta := outputMeta{Version: build.Version}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				meta.Commit = setting.Value
+				break
+			}
+		}
+	}
+
+	return meta
+}
+
+// writeOutputFile writes data as JSON to config.opts.output, if set, in
+// addition to whatever the run already printed to the terminal. This lets a
+// pipeline capture machine-readable results to a file while the TTY still
+// shows the usual human-readable summary, without needing --format to
+// suppress that summary or running the command twice. The write is atomic
+// (see atomicWriteFile), so a failure partway through never leaves a
+// truncated file behind. The indentation width is controlled by --indent.
+// With --include-meta, data is wrapped in an outputEnvelope recording the
+// producing binary's version and commit. With --gzip, the file is
+// gzip-compressed as it's written.
+func writeOutputFile(config templateConfig, data interface{}) error {
+	if config.opts.output == "" {
+		return nil
+	}
+
+	if config.opts.includeMeta {
+		data = outputEnvelope{Meta: buildOutputMeta(), Data: data}
+	}
+
+	return atomicWriteFile(config.opts.output, config.opts.gzip, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", jsonIndent(config.opts.indent))
+		return enc.Encode(data)
+	})
+}
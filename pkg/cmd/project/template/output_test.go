@@ -0,0 +1,55 @@
+package template
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildOutputMeta_Deterministic(t *testing.T) {
+	first := buildOutputMeta()
+	second := buildOutputMeta()
+	assert.Equal(t, first, second)
+}
+
+func TestWriteOutputFile_IncludeMeta(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "output-*.json")
+	assert.NoError(t, err)
+	path := f.Name()
+	assert.NoError(t, f.Close())
+
+	config := templateConfig{
+		opts: templateOpts{output: path, includeMeta: true, indent: 2},
+	}
+
+	err = writeOutputFile(config, map[string]string{"number": "1"})
+	assert.NoError(t, err)
+
+	raw, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var envelope outputEnvelope
+	assert.NoError(t, json.Unmarshal(raw, &envelope))
+	assert.Equal(t, buildOutputMeta(), envelope.Meta)
+	assert.Equal(t, map[string]interface{}{"number": "1"}, envelope.Data)
+}
+
+func TestWriteOutputFile_WithoutIncludeMeta(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "output-*.json")
+	assert.NoError(t, err)
+	path := f.Name()
+	assert.NoError(t, f.Close())
+
+	config := templateConfig{
+		opts: templateOpts{output: path, indent: 2},
+	}
+
+	err = writeOutputFile(config, map[string]string{"number": "1"})
+	assert.NoError(t, err)
+
+	raw, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), `"meta"`)
+}
@@ -0,0 +1,65 @@
+package template
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// batchProgress reports on-screen progress for a long-running batch
+// operation by updating a single spinner label as entries complete,
+// instead of printing a line per entry. It is backed by the shared
+// iostreams progress indicator, which already suppresses itself outside a
+// TTY, so batchProgress only has to add the --quiet/--format opt-outs on
+// top of that.
+type batchProgress struct {
+	config  templateConfig
+	enabled bool
+	total   int
+	done    int64
+}
+
+// newBatchProgress returns a batchProgress for a batch of total entries.
+// total is 0 for a batch whose size isn't known upfront (e.g. --search),
+// in which case the label reports a running count instead of a
+// percentage.
+func newBatchProgress(config templateConfig, total int) *batchProgress {
+	return &batchProgress{
+		config:  config,
+		enabled: !config.opts.quiet && config.opts.exporter == nil,
+		total:   total,
+	}
+}
+
+// start shows the initial label, before any entries have completed.
+func (p *batchProgress) start() {
+	if !p.enabled {
+		return
+	}
+	p.config.io.StartProgressIndicatorWithLabel(p.label(0))
+}
+
+// increment marks one more entry complete and updates the displayed
+// label. Safe to call from multiple goroutines at once.
+func (p *batchProgress) increment() {
+	if !p.enabled {
+		return
+	}
+	done := atomic.AddInt64(&p.done, 1)
+	p.config.io.StartProgressIndicatorWithLabel(p.label(int(done)))
+}
+
+// label formats the progress text for done completed entries.
+func (p *batchProgress) label(done int) string {
+	if p.total > 0 {
+		return fmt.Sprintf("Processing %d/%d (%d%%)", done, p.total, done*100/p.total)
+	}
+	return fmt.Sprintf("Processing %d", done)
+}
+
+// stop hides the progress indicator once the batch has finished.
+func (p *batchProgress) stop() {
+	if !p.enabled {
+		return
+	}
+	p.config.io.StopProgressIndicator()
+}
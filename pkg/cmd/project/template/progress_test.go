@@ -0,0 +1,66 @@
+package template
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchProgress_Bounded(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{opts: templateOpts{}, io: ios}
+
+	p := newBatchProgress(config, 4)
+	assert.Equal(t, "Processing 0/4 (0%)", p.label(0))
+
+	p.increment()
+	p.increment()
+	assert.Equal(t, "Processing 2/4 (50%)", p.label(int(p.done)))
+
+	p.increment()
+	p.increment()
+	assert.Equal(t, "Processing 4/4 (100%)", p.label(int(p.done)))
+}
+
+func TestBatchProgress_Unbounded(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{opts: templateOpts{}, io: ios}
+
+	p := newBatchProgress(config, 0)
+	p.increment()
+	p.increment()
+	p.increment()
+	assert.Equal(t, "Processing 3", p.label(int(p.done)))
+}
+
+func TestBatchProgress_ConcurrentIncrement(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{opts: templateOpts{}, io: ios}
+
+	p := newBatchProgress(config, 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.increment()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(50), p.done)
+}
+
+func TestBatchProgress_DisabledUnderQuietAndExporter(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+
+	quiet := newBatchProgress(templateConfig{opts: templateOpts{quiet: true}, io: ios}, 4)
+	assert.False(t, quiet.enabled)
+
+	jsonOutput := newBatchProgress(templateConfig{opts: templateOpts{exporter: cmdutil.NewJSONExporter()}, io: ios}, 4)
+	assert.False(t, jsonOutput.enabled)
+}
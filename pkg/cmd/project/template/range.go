@@ -0,0 +1,57 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseRange parses a "<start>-<end>" range expression, as accepted by
+// --range, into the inclusive list of project numbers it expands to. Both
+// ends must be positive, and start must not exceed end.
+func parseRange(raw string) ([]int32, error) {
+	start, end, ok := strings.Cut(raw, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid range %q: expected \"<start>-<end>\"", raw)
+	}
+
+	startN, err := strconv.ParseInt(strings.TrimSpace(start), 10, 32)
+	if err != nil || startN <= 0 {
+		return nil, fmt.Errorf("invalid range %q: start must be a positive number", raw)
+	}
+	endN, err := strconv.ParseInt(strings.TrimSpace(end), 10, 32)
+	if err != nil || endN <= 0 {
+		return nil, fmt.Errorf("invalid range %q: end must be a positive number", raw)
+	}
+	if startN > endN {
+		return nil, fmt.Errorf("invalid range %q: start must not exceed end", raw)
+	}
+
+	numbers := make([]int32, 0, endN-startN+1)
+	for n := startN; n <= endN; n++ {
+		numbers = append(numbers, int32(n))
+	}
+	return numbers, nil
+}
+
+// runRange expands --range into one manifest entry per number, all sharing
+// config.opts.owner and config.opts.undo, and processes them the same way
+// --from-file does, including its --concurrency/--owner-concurrency
+// behavior and --confirm-threshold guard.
+func runRange(config templateConfig) error {
+	numbers, err := parseRange(config.opts.rangeExpr)
+	if err != nil {
+		return err
+	}
+
+	if err := confirmBulkOperation(config, len(numbers), fmt.Sprintf("%d projects in --owner %s", len(numbers), config.opts.owner), nil); err != nil {
+		return err
+	}
+
+	entries := make([]manifestEntry, len(numbers))
+	for i, number := range numbers {
+		entries[i] = manifestEntry{Owner: config.opts.owner, Number: number, Undo: config.opts.undo}
+	}
+
+	return runEntries(config, entries)
+}
@@ -0,0 +1,266 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []int32
+		wantErr string
+	}{
+		{
+			name: "single-element range",
+			raw:  "1-1",
+			want: []int32{1},
+		},
+		{
+			name: "multi-element range",
+			raw:  "1-10",
+			want: []int32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			name:    "no separator",
+			raw:     "5",
+			wantErr: `invalid range "5": expected "<start>-<end>"`,
+		},
+		{
+			name:    "non-numeric start",
+			raw:     "a-5",
+			wantErr: `invalid range "a-5": start must be a positive number`,
+		},
+		{
+			name:    "non-positive start",
+			raw:     "0-5",
+			wantErr: `invalid range "0-5": start must be a positive number`,
+		},
+		{
+			name:    "non-positive end",
+			raw:     "1--5",
+			wantErr: `invalid range "1--5": end must be a positive number`,
+		},
+		{
+			name:    "start after end",
+			raw:     "10-1",
+			wantErr: `invalid range "10-1": start must not exceed end`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRange(tt.raw)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunRange(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"query": "query UserOrgOwner.*",
+			"variables": map[string]interface{}{
+				"login": "github",
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"id": "org ID"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{"type": "NOT_FOUND", "path": []string{"user"}},
+			},
+		})
+
+	for _, number := range []int{1, 2} {
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			MatchType("json").
+			JSON(map[string]interface{}{
+				"query": "query OrgProject.*",
+				"variables": map[string]interface{}{
+					"login":       "github",
+					"number":      number,
+					"firstItems":  0,
+					"afterItems":  nil,
+					"firstFields": 0,
+					"afterFields": nil,
+				},
+			}).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"data": map[string]interface{}{
+					"organization": map[string]interface{}{
+						"projectV2": map[string]interface{}{"id": "project ID"},
+					},
+				},
+			})
+
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"data": map[string]interface{}{
+					"markProjectV2AsTemplate": map[string]interface{}{
+						"projectV2": map[string]interface{}{"id": "project ID", "number": number},
+					},
+				},
+			})
+	}
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:   templateOpts{owner: "github", rangeExpr: "1-2"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runRange(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "2 processed, 2 marked")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+// TestRunRange_ResolvesOwnerOnce asserts that a --range spanning 10 numbers
+// under a single --owner resolves that owner's node ID exactly once and
+// reuses it for every number, rather than once per number. The owner mock
+// below is registered without .Persist(), so gock consumes it on the first
+// match; a second NewOwner call would fail to match any request and fail
+// the run.
+func TestRunRange_ResolvesOwnerOnce(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"query": "query UserOrgOwner.*",
+			"variables": map[string]interface{}{
+				"login": "github",
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"id": "org ID"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{"type": "NOT_FOUND", "path": []string{"user"}},
+			},
+		})
+
+	for number := 1; number <= 10; number++ {
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			MatchType("json").
+			JSON(map[string]interface{}{
+				"query": "query OrgProject.*",
+				"variables": map[string]interface{}{
+					"login":       "github",
+					"number":      number,
+					"firstItems":  0,
+					"afterItems":  nil,
+					"firstFields": 0,
+					"afterFields": nil,
+				},
+			}).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"data": map[string]interface{}{
+					"organization": map[string]interface{}{
+						"projectV2": map[string]interface{}{"id": "project ID"},
+					},
+				},
+			})
+
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"data": map[string]interface{}{
+					"markProjectV2AsTemplate": map[string]interface{}{
+						"projectV2": map[string]interface{}{"id": "project ID", "number": number},
+					},
+				},
+			})
+	}
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:   templateOpts{owner: "github", rangeExpr: "1-10"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runRange(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "10 processed, 10 marked")
+	assert.False(t, gock.HasUnmatchedRequest())
+	assert.False(t, gock.IsPending())
+}
+
+func TestRunRange_ConfirmThreshold(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts: templateOpts{
+			owner:            "github",
+			rangeExpr:        "1-5",
+			confirmThreshold: 2,
+		},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runRange(config)
+	assert.ErrorContains(t, err, "--yes is required")
+}
+
+// TestRunRange_MaxAffected asserts that --max-affected hard-stops --range
+// before any mutation once the expanded range exceeds the limit, even with
+// --yes -- unlike --confirm-threshold, it isn't a prompt --yes can satisfy.
+func TestRunRange_MaxAffected(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts: templateOpts{
+			owner:       "github",
+			rangeExpr:   "1-5",
+			maxAffected: 2,
+			yes:         true,
+		},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runRange(config)
+	assert.EqualError(t, err, "refusing to proceed: this operation would affect 5 projects, which exceeds --max-affected 2")
+	assert.False(t, gock.HasUnmatchedRequest())
+	assert.False(t, gock.IsPending())
+}
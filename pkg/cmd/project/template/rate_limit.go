@@ -0,0 +1,47 @@
+package template
+
+import (
+	"fmt"
+	"time"
+)
+
+// checkRateLimit probes the primary GraphQL rate limit before runTemplate's
+// main operation. If it is exhausted, this reports a clear "resets at HH:MM
+// (in Nm)" error instead of letting the operation fail partway through with
+// GitHub's own rate-limit error, which gives no indication of when to retry.
+// With --wait-for-ratelimit, it sleeps until the reset instead of erroring.
+// The probe itself is best-effort: if it fails, the real operation is left
+// to surface its own error rather than being blocked by a failed check.
+func checkRateLimit(config templateConfig) error {
+	limit, err := config.client.RateLimit()
+	if err != nil {
+		return nil
+	}
+	if limit.Remaining > 0 {
+		return nil
+	}
+
+	if !config.opts.waitForRateLimit {
+		return fmt.Errorf("rate limit exhausted; resets at %s (in %s)", limit.ResetAt.Local().Format("15:04"), formatCountdown(limit.ResetAt.Sub(config.opts.now())))
+	}
+
+	wait := limit.ResetAt.Sub(config.opts.now())
+	if wait < 0 {
+		wait = 0
+	}
+	if config.io.IsStderrTTY() && !config.opts.quiet {
+		fmt.Fprintf(config.io.ErrOut, "rate limit exhausted; waiting %s for reset at %s\n", formatCountdown(wait), limit.ResetAt.Local().Format("15:04"))
+	}
+	config.opts.sleep(wait)
+	return nil
+}
+
+// formatCountdown renders d as whole minutes, rounding up so a reset a few
+// seconds away is never reported as "in 0m".
+func formatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	minutes := int((d + time.Minute - time.Nanosecond) / time.Minute)
+	return fmt.Sprintf("%dm", minutes)
+}
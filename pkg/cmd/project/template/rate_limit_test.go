@@ -0,0 +1,142 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{
+		{name: "exact minute", d: 5 * time.Minute, want: "5m"},
+		{name: "rounds up", d: 4*time.Minute + 30*time.Second, want: "5m"},
+		{name: "past reset", d: -time.Minute, want: "0m"},
+		{name: "under a minute", d: 30 * time.Second, want: "1m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatCountdown(tt.d))
+		})
+	}
+}
+
+func TestCheckRateLimit_NotExhausted(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query RateLimit.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"rateLimit": map[string]interface{}{
+					"remaining": 100,
+					"resetAt":   "2024-01-01T01:00:00Z",
+				},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		client: queries.NewTestClient(),
+		opts:   templateOpts{now: time.Now},
+		io:     ios,
+	}
+
+	assert.NoError(t, checkRateLimit(config))
+}
+
+func TestCheckRateLimit_ExhaustedWithoutWait(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query RateLimit.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"rateLimit": map[string]interface{}{
+					"remaining": 0,
+					"resetAt":   "2024-01-01T01:10:00Z",
+				},
+			},
+		})
+
+	fixedNow := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		client: queries.NewTestClient(),
+		opts:   templateOpts{now: func() time.Time { return fixedNow }},
+		io:     ios,
+	}
+
+	err := checkRateLimit(config)
+	assert.EqualError(t, err, "rate limit exhausted; resets at "+time.Date(2024, 1, 1, 1, 10, 0, 0, time.UTC).Local().Format("15:04")+" (in 10m)")
+}
+
+func TestCheckRateLimit_WaitForReset(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query RateLimit.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"rateLimit": map[string]interface{}{
+					"remaining": 0,
+					"resetAt":   "2024-01-01T01:05:00Z",
+				},
+			},
+		})
+
+	fixedNow := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		client: queries.NewTestClient(),
+		opts: templateOpts{
+			waitForRateLimit: true,
+			now:              func() time.Time { return fixedNow },
+			sleep:            func(d time.Duration) { slept = d },
+		},
+		io: ios,
+	}
+
+	err := checkRateLimit(config)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, slept)
+	assert.Contains(t, stderr.String(), "rate limit exhausted; waiting 5m for reset at")
+}
+
+func TestCheckRateLimit_ProbeFailureIsNotFatal(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query RateLimit.*`).
+		Reply(500)
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		client: queries.NewTestClient(),
+		opts:   templateOpts{now: time.Now},
+		io:     ios,
+	}
+
+	assert.NoError(t, checkRateLimit(config))
+}
@@ -0,0 +1,40 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/ghrepo"
+)
+
+// resolveRepoOwner resolves config.opts.repo ("owner/name") to the login of
+// the repository's owner, for --repo: a convenience for Actions workflows
+// that know their repository (e.g. via ${{ github.repository }}) but don't
+// want to hardcode the owning org, reusing the same OWNER/REPO resolution
+// --link-repo uses.
+func resolveRepoOwner(config templateConfig) (string, error) {
+	owner, name, ok := strings.Cut(config.opts.repo, "/")
+	if !ok {
+		return "", fmt.Errorf("expected the \"OWNER/REPO\" format, got %q", config.opts.repo)
+	}
+
+	httpClient, err := config.httpClient()
+	if err != nil {
+		return "", err
+	}
+	c := api.NewClientFromHTTP(httpClient)
+
+	cfg, err := config.cfg()
+	if err != nil {
+		return "", err
+	}
+	host, _ := cfg.Authentication().DefaultHost()
+
+	repo, err := api.GitHubRepo(c, ghrepo.NewWithHost(owner, name, host))
+	if err != nil {
+		return "", err
+	}
+
+	return repo.Owner.Login, nil
+}
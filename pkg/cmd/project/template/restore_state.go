@@ -0,0 +1,193 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+)
+
+// restoreStateChange describes one project whose template flag needs to
+// change to match a --restore-state snapshot.
+type restoreStateChange struct {
+	Number int32
+	Want   bool
+}
+
+// restoreResult records the outcome of restoring one project's template flag.
+type restoreResult struct {
+	Project queries.Project `json:"project"`
+	Want    bool            `json:"want"`
+	Error   string          `json:"error,omitempty"`
+	Code    errorCode       `json:"code,omitempty"`
+}
+
+// loadStateDump reads and parses the JSON document written by `template
+// audit --dump-state` at path (use "-" to read from stdin).
+func loadStateDump(path string, config templateConfig) (templateStateDump, error) {
+	data, err := cmdutil.ReadFile(path, config.io.In)
+	if err != nil {
+		return templateStateDump{}, err
+	}
+
+	var dump templateStateDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return templateStateDump{}, fmt.Errorf("could not parse --restore-state file: %w", err)
+	}
+	return dump, nil
+}
+
+// diffStateDump compares dump against the owner's current template gallery
+// membership and returns the changes needed to bring every recorded project
+// back to its dumped state, in ascending project number order. Projects
+// already in the desired state are omitted.
+func diffStateDump(dump templateStateDump, current queries.TemplateProjects) []restoreStateChange {
+	isTemplate := make(map[int32]bool, len(current.Nodes))
+	for _, t := range current.Nodes {
+		isTemplate[t.Number] = true
+	}
+
+	var changes []restoreStateChange
+	for raw, want := range dump.Projects {
+		num, err := strconv.Atoi(raw)
+		if err != nil {
+			continue
+		}
+		number := int32(num)
+		if isTemplate[number] == want {
+			continue
+		}
+		changes = append(changes, restoreStateChange{Number: number, Want: want})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Number < changes[j].Number })
+	return changes
+}
+
+// printRestorePreview lists the changes a --restore-state run would make,
+// without making them. Used both for --dry-run and ahead of the
+// confirmation prompt for a real run.
+func printRestorePreview(config templateConfig, changes []restoreStateChange) error {
+	for _, c := range changes {
+		verb := "mark"
+		if !c.Want {
+			verb = "unmark"
+		}
+		if _, err := fmt.Fprintf(config.io.Out, "would %s project %d as a template\n", verb, c.Number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmRestoreState requires --yes, or an interactive confirmation, before
+// applying a --restore-state run's changes, since a stale or mismatched
+// snapshot can affect a large and unpredictable set of projects.
+func confirmRestoreState(config templateConfig, count int) error {
+	if err := checkMaxAffected(config, count); err != nil {
+		return err
+	}
+
+	if config.opts.yes {
+		return nil
+	}
+	if !config.io.CanPrompt() {
+		return cmdutil.FlagErrorf("--yes is required to apply a --restore-state run (would change %d projects)", count)
+	}
+	confirmed, err := config.prompter.Confirm(fmt.Sprintf("This will change the template flag of %d projects to match %s. Continue?", count, config.opts.restoreState), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cmdutil.CancelError
+	}
+	return nil
+}
+
+// runRestoreState applies a --dump-state snapshot back to its owner,
+// marking or unmarking each recorded project to match, and skipping any
+// project already in its recorded state. It is a disaster-recovery
+// counterpart to `template audit --dump-state`.
+func runRestoreState(config templateConfig) error {
+	dump, err := loadStateDump(config.opts.restoreState, config)
+	if err != nil {
+		return err
+	}
+
+	current, err := config.client.TemplateProjects(dump.Owner)
+	if err != nil {
+		return err
+	}
+
+	changes := diffStateDump(dump, current)
+	skipped := len(dump.Projects) - len(changes)
+
+	if len(changes) == 0 {
+		if config.io.IsStderrTTY() {
+			fmt.Fprintln(config.io.ErrOut, "Already matches the recorded state; nothing to do.")
+		}
+		return nil
+	}
+
+	if config.opts.dryRun {
+		return printRestorePreview(config, changes)
+	}
+
+	if err := confirmRestoreState(config, len(changes)); err != nil {
+		return err
+	}
+
+	owner, err := config.client.NewOwner(config.io.CanPrompt(), dump.Owner)
+	if err != nil {
+		return err
+	}
+
+	summary := batchSummary{Skipped: skipped}
+	results := make([]restoreResult, 0, len(changes))
+
+	for _, c := range changes {
+		project, err := config.client.NewProject(false, owner, c.Number, false)
+		if err != nil {
+			summary.Failed++
+			results = append(results, restoreResult{Want: c.Want, Error: err.Error(), Code: classifyError(err)})
+			continue
+		}
+
+		entryConfig := config
+		entryConfig.opts.projectID = project.ID
+		entryConfig.opts.undo = !c.Want
+
+		mutated, _, noop, err := applyMutation(entryConfig, owner)
+		if err != nil {
+			summary.Failed++
+			results = append(results, restoreResult{Project: *project, Want: c.Want, Error: err.Error(), Code: classifyError(err)})
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case noop:
+			summary.Noop++
+		case c.Want:
+			summary.Marked++
+		default:
+			summary.Unmarked++
+		}
+		results = append(results, restoreResult{Project: mutated, Want: c.Want})
+	}
+
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, results); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, results)
+}
@@ -0,0 +1,260 @@
+package template
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cli/cli/v2/internal/prompter"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestDiffStateDump(t *testing.T) {
+	dump := templateStateDump{
+		Owner: "github",
+		Projects: map[string]bool{
+			"1": true,  // already a template: no change
+			"2": true,  // not yet a template: mark
+			"3": false, // no longer in the dump's desired state: unmark
+			"4": false, // already not a template: no change
+		},
+	}
+	current := queries.TemplateProjects{
+		Nodes: []queries.TemplateProject{
+			{Number: 1},
+			{Number: 3},
+		},
+	}
+
+	changes := diffStateDump(dump, current)
+	assert.Equal(t, []restoreStateChange{
+		{Number: 2, Want: true},
+		{Number: 3, Want: false},
+	}, changes)
+}
+
+func TestRunRestoreState_DryRun(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 0,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes":      []interface{}{},
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/state.json"
+	writeTestFile(t, path, `{"owner":"github","timestamp":"2024-01-01T00:00:00Z","projects":{"1":true}}`)
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{restoreState: path, dryRun: true},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runRestoreState(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "would mark project 1 as a template\n", stdout.String())
+}
+
+func TestRunRestoreState_RequiresYesNonInteractive(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 0,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes":      []interface{}{},
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/state.json"
+	writeTestFile(t, path, `{"owner":"github","timestamp":"2024-01-01T00:00:00Z","projects":{"1":true}}`)
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{restoreState: path},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runRestoreState(config)
+	assert.EqualError(t, err, "--yes is required to apply a --restore-state run (would change 1 projects)")
+}
+
+func TestRunRestoreState_NothingToChange(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes": []interface{}{
+							map[string]interface{}{
+								"number":    1,
+								"title":     "Roadmap",
+								"url":       "https://github.com/orgs/github/projects/1",
+								"public":    true,
+								"updatedAt": "2024-01-02T15:04:05Z",
+								"items":     map[string]interface{}{"totalCount": 3},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/state.json"
+	writeTestFile(t, path, `{"owner":"github","timestamp":"2024-01-01T00:00:00Z","projects":{"1":true}}`)
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:   templateOpts{restoreState: path},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runRestoreState(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "Already matches the recorded state; nothing to do.\n", stderr.String())
+}
+
+func TestRunRestoreState(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 0,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes":      []interface{}{},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"query": "query OrgProject.*",
+			"variables": map[string]interface{}{
+				"login":       "github",
+				"number":      1,
+				"firstItems":  0,
+				"afterItems":  nil,
+				"firstFields": 0,
+				"afterFields": nil,
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"id":     "project ID",
+						"number": 1,
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query UserOrgOwner.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"login": "github", "id": "org ID"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{
+					"type": "NOT_FOUND",
+					"path": []string{"user"},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"id":     "project ID",
+						"number": 1,
+					},
+				},
+			},
+		})
+
+	dir := t.TempDir()
+	path := dir + "/state.json"
+	writeTestFile(t, path, `{"owner":"github","timestamp":"2024-01-01T00:00:00Z","projects":{"1":true}}`)
+
+	ios, _, stdout, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:     templateOpts{restoreState: path, yes: true, summaryFormat: "text"},
+		client:   queries.NewTestClient(),
+		io:       ios,
+		prompter: &prompter.PrompterMock{},
+	}
+
+	err := runRestoreState(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "", stdout.String())
+	assert.Equal(t, "1 processed, 1 marked, 0 unmarked, 0 noop, 0 skipped, 0 failed\n", stderr.String())
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+}
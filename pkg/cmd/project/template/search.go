@@ -0,0 +1,123 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+)
+
+// searchMatch records the outcome of templating one project matched by
+// --search.
+type searchMatch struct {
+	Project queries.Project `json:"project"`
+	Error   string          `json:"error,omitempty"`
+	Code    errorCode       `json:"code,omitempty"`
+}
+
+// runSearch templates every project matched by config.opts.search, a raw
+// GitHub search query run with type: PROJECT. This requires the host's
+// GraphQL schema to actually support PROJECT-scoped search (see
+// queries.ProjectSearchSupported), and errors clearly if it doesn't, rather
+// than letting a confusing raw GraphQL error through. A search's matches can
+// span projects across many owners that the operator hasn't reviewed by
+// number or title, so --search always requires confirmation (or --yes)
+// regardless of --confirm-threshold, the same way --field-value does. A
+// TTY shows a running count as matches are processed.
+func runSearch(config templateConfig) error {
+	matches, err := config.client.SearchProjects(config.opts.search)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		return printEmptyProjectList(config)
+	}
+
+	if config.io.IsStderrTTY() && !config.opts.quiet {
+		fmt.Fprintf(config.io.ErrOut, "--search %q matched %d projects\n", config.opts.search, len(matches))
+	}
+
+	if err := confirmSearchOperation(config, len(matches)); err != nil {
+		return err
+	}
+
+	summary := batchSummary{}
+	results := make([]searchMatch, 0, len(matches))
+
+	progress := newBatchProgress(config, 0)
+	progress.start()
+
+	for _, p := range matches {
+		entryConfig := config
+		entryConfig.opts.projectID = p.ID
+
+		mutated, _, noop, err := applyMutation(entryConfig, nil)
+		progress.increment()
+		if err != nil {
+			summary.Failed++
+			results = append(results, searchMatch{Project: p, Error: err.Error(), Code: classifyError(err)})
+			continue
+		}
+
+		summary.Processed++
+		switch {
+		case noop:
+			summary.Noop++
+		case config.opts.undo:
+			summary.Unmarked++
+		default:
+			summary.Marked++
+		}
+		results = append(results, searchMatch{Project: mutated})
+	}
+
+	progress.stop()
+
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+
+	var output interface{} = results
+	if config.opts.groupByOwner {
+		grouped := groupSearchMatchesByOwner(results)
+		if err := printGroupedSearchMatches(config, grouped); err != nil {
+			return err
+		}
+		output = grouped
+	}
+
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, output); err != nil {
+			return err
+		}
+	}
+
+	return writeOutputFile(config, output)
+}
+
+// confirmSearchOperation unconditionally requires confirmation (or --yes
+// when the command cannot prompt) before templating count projects matched
+// by --search.
+func confirmSearchOperation(config templateConfig, count int) error {
+	if err := checkMaxAffected(config, count); err != nil {
+		return err
+	}
+
+	if config.opts.yes {
+		return nil
+	}
+
+	if !config.io.CanPrompt() {
+		return cmdutil.FlagErrorf("--yes is required when templating projects matched by --search (matched %d)", count)
+	}
+
+	confirmed, err := config.prompter.Confirm(fmt.Sprintf("This will %s %d projects matched by --search %q. Continue?", verbForUndo(config.opts.undo), count, config.opts.search), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cmdutil.CancelError
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRunSearch_Unsupported(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectSearch_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"SearchType": map[string]interface{}{
+					"enumValues": []interface{}{
+						map[string]interface{}{"name": "ISSUE"},
+						map[string]interface{}{"name": "REPOSITORY"},
+					},
+				},
+			},
+		})
+
+	client := queries.NewTestClient()
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{search: "org:github is:open", yes: true},
+		client: client,
+		io:     ios,
+	}
+
+	err := runSearch(config)
+	assert.EqualError(t, err, "this host's GraphQL schema does not support searching for projects")
+}
+
+func TestRunSearch(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectSearch_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"SearchType": map[string]interface{}{
+					"enumValues": []interface{}{
+						map[string]interface{}{"name": "PROJECT"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query SearchProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"search": map[string]interface{}{
+					"nodes": []interface{}{
+						map[string]interface{}{"id": "project ID", "number": 1},
+					},
+					"pageInfo": map[string]interface{}{"hasNextPage": false},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation MarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"markProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID", "number": 1},
+				},
+			},
+		})
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:   templateOpts{search: "org:github is:open", yes: true},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runSearch(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), `--search "org:github is:open" matched 1 projects`)
+	assert.Contains(t, stderr.String(), "1 processed, 1 marked")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestRunSearch_RequiresYes(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectSearch_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"SearchType": map[string]interface{}{
+					"enumValues": []interface{}{
+						map[string]interface{}{"name": "PROJECT"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query SearchProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"search": map[string]interface{}{
+					"nodes": []interface{}{
+						map[string]interface{}{"id": "project ID", "number": 1},
+					},
+					"pageInfo": map[string]interface{}{"hasNextPage": false},
+				},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts:   templateOpts{search: "org:github is:open"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runSearch(config)
+	assert.ErrorContains(t, err, "--yes is required")
+}
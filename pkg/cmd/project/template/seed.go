@@ -0,0 +1,83 @@
+package template
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// resolveSeed picks the RNG seed used to jitter --verify retry backoff: the
+// explicit --seed flag if it was set, then GH_PROJECT_SEED, then a
+// time-based default. --seed and GH_PROJECT_SEED exist for reproducing a
+// specific backoff sequence in tests and debugging; they are intentionally
+// undocumented/hidden, since normal use should never need them.
+func resolveSeed(flagSeed int64, flagChanged bool, env string) int64 {
+	if flagChanged {
+		return flagSeed
+	}
+	if env != "" {
+		if n, err := strconv.ParseInt(env, 10, 64); err == nil {
+			return n
+		}
+	}
+	return time.Now().UnixNano()
+}
+
+// jitterBackoff adjusts base by up to +/-20% of randomness drawn from rng,
+// so that --verify retries across many concurrent invocations don't all
+// retry in lockstep, while remaining exactly reproducible for a fixed seed.
+func jitterBackoff(base time.Duration, rng *rand.Rand) time.Duration {
+	if base <= 0 || rng == nil {
+		return base
+	}
+
+	spread := float64(base) * 0.2
+	delta := (rng.Float64()*2 - 1) * spread
+	jittered := time.Duration(float64(base) + delta)
+	if jittered < 0 {
+		return 0
+	}
+	return jittered
+}
+
+// exponentialBackoff doubles base for each retry after the first (attempt is
+// 1-indexed), so that --verify-interval is only the delay before the first
+// retry and later retries back off further apart. It stops doubling as soon
+// as it would reach maxBackoff (or would overflow time.Duration), since the
+// caller clamps to maxBackoff anyway. maxBackoff of 0 disables the cap.
+func exponentialBackoff(base, maxBackoff time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return base
+	}
+
+	delay := base
+	for i := 1; i < attempt; i++ {
+		if maxBackoff > 0 && delay >= maxBackoff {
+			break
+		}
+		next := delay * 2
+		if next < delay {
+			// overflowed time.Duration's int64; delay is already far past
+			// any sane maxBackoff, so stop growing it.
+			break
+		}
+		delay = next
+	}
+
+	if maxBackoff > 0 && delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
+// backoffForAttempt computes the jittered, exponentially-growing delay
+// before retrying for the attempt'th time (1-indexed), capped at maxBackoff
+// so a long run of retries never sleeps for minutes at a stretch. A
+// maxBackoff of 0 disables the cap.
+func backoffForAttempt(base, maxBackoff time.Duration, attempt int, rng *rand.Rand) time.Duration {
+	backoff := jitterBackoff(exponentialBackoff(base, maxBackoff, attempt), rng)
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
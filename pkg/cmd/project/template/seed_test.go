@@ -0,0 +1,96 @@
+package template
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		flagSeed    int64
+		flagChanged bool
+		env         string
+		want        int64
+	}{
+		{name: "flag wins", flagSeed: 42, flagChanged: true, env: "7", want: 42},
+		{name: "env when flag unset", flagChanged: false, env: "7", want: 7},
+		{name: "invalid env falls through to time-based", flagChanged: false, env: "not-a-number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveSeed(tt.flagSeed, tt.flagChanged, tt.env)
+			if tt.want != 0 {
+				assert.Equal(t, tt.want, got)
+			} else {
+				assert.NotEqual(t, int64(0), got)
+			}
+		})
+	}
+}
+
+func TestJitterBackoff_DeterministicForFixedSeed(t *testing.T) {
+	backoffSequence := func(seed int64) []time.Duration {
+		rng := rand.New(rand.NewSource(seed))
+		seq := make([]time.Duration, 5)
+		for i := range seq {
+			seq[i] = jitterBackoff(2*time.Second, rng)
+		}
+		return seq
+	}
+
+	first := backoffSequence(12345)
+	second := backoffSequence(12345)
+	assert.Equal(t, first, second)
+
+	third := backoffSequence(54321)
+	assert.NotEqual(t, first, third)
+}
+
+func TestJitterBackoff_WithinBounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := 2 * time.Second
+	for i := 0; i < 100; i++ {
+		got := jitterBackoff(base, rng)
+		assert.GreaterOrEqual(t, got, base*4/5)
+		assert.LessOrEqual(t, got, base*6/5)
+	}
+}
+
+func TestJitterBackoff_NoRNGReturnsBase(t *testing.T) {
+	assert.Equal(t, 2*time.Second, jitterBackoff(2*time.Second, nil))
+}
+
+func TestExponentialBackoff_GrowsAndCaps(t *testing.T) {
+	base := 2 * time.Second
+	maxBackoff := 10 * time.Second
+
+	assert.Equal(t, 2*time.Second, exponentialBackoff(base, maxBackoff, 1))
+	assert.Equal(t, 4*time.Second, exponentialBackoff(base, maxBackoff, 2))
+	assert.Equal(t, 8*time.Second, exponentialBackoff(base, maxBackoff, 3))
+	assert.Equal(t, maxBackoff, exponentialBackoff(base, maxBackoff, 4))
+	assert.Equal(t, maxBackoff, exponentialBackoff(base, maxBackoff, 50))
+}
+
+func TestExponentialBackoff_NoCap(t *testing.T) {
+	got := exponentialBackoff(2*time.Second, 0, 10)
+	assert.Equal(t, 2*time.Second*512, got)
+}
+
+func TestBackoffForAttempt_NeverExceedsCap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := 500 * time.Millisecond
+	maxBackoff := 5 * time.Second
+
+	for attempt := 1; attempt <= 50; attempt++ {
+		for i := 0; i < 20; i++ {
+			got := backoffForAttempt(base, maxBackoff, attempt, rng)
+			assert.LessOrEqual(t, got, maxBackoff)
+			assert.GreaterOrEqual(t, got, time.Duration(0))
+		}
+	}
+}
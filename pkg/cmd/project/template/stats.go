@@ -0,0 +1,179 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/tableprinter"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/client"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type statsOpts struct {
+	owner    string
+	exporter cmdutil.Exporter
+}
+
+type statsConfig struct {
+	client *queries.Client
+	opts   statsOpts
+	io     *iostreams.IOStreams
+}
+
+// templateDerivativeStat is one template project's derivative count, a row
+// in templateStats.Templates.
+type templateDerivativeStat struct {
+	Number      int32  `json:"number"`
+	Title       string `json:"title"`
+	Derivatives int    `json:"derivatives"`
+}
+
+// templateStats is the aggregate result of `template stats`: how much of an
+// owner's project gallery is templated, and how widely each template has
+// been reused. DerivativesSupported reflects the same capability probe
+// `template derivatives` uses, so a host whose GraphQL schema doesn't expose
+// derived projects still gets a coverage ratio instead of a hard failure.
+type templateStats struct {
+	Owner                string                   `json:"owner"`
+	TotalProjects        int                      `json:"totalProjects"`
+	TemplateCount        int                      `json:"templateCount"`
+	TemplateRatio        float64                  `json:"templateRatio"`
+	DerivativesSupported bool                     `json:"derivativesSupported"`
+	TotalDerivatives     int                      `json:"totalDerivatives,omitempty"`
+	Templates            []templateDerivativeStat `json:"templates,omitempty"`
+}
+
+// newCmdStats returns the read-only `gh project template stats` subcommand,
+// which reports what fraction of an owner's projects are templates and how
+// many derivative projects each one has, for governance dashboards that
+// track template coverage over time.
+func newCmdStats(f *cmdutil.Factory, runF func(config statsConfig) error) *cobra.Command {
+	opts := statsOpts{}
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize template coverage for an organization's projects",
+		Example: heredoc.Doc(`
+			# how much of the github org's project gallery is templated, and how reused each template is
+			gh project template stats --owner github
+
+			# as JSON, for a dashboard
+			gh project template stats --owner github --format json
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.owner == "" {
+				return cmdutil.FlagErrorf("--owner is required")
+			}
+
+			client, err := client.New(f)
+			if err != nil {
+				return err
+			}
+
+			config := statsConfig{
+				client: client,
+				opts:   opts,
+				io:     f.IOStreams,
+			}
+
+			if runF != nil {
+				return runF(config)
+			}
+			return runStats(config)
+		},
+	}
+
+	statsCmd.Flags().StringVar(&opts.owner, "owner", "", "Login of the organization to summarize.")
+	cmdutil.AddFormatFlags(statsCmd, &opts.exporter)
+
+	return statsCmd
+}
+
+func runStats(config statsConfig) error {
+	// Projects.TotalCount reflects the organization's full project count
+	// regardless of how many nodes are fetched, so a limit of 1 is enough to
+	// read it without paginating through every project.
+	projects, err := config.client.Projects(config.opts.owner, queries.OrgOwner, 1, false)
+	if err != nil {
+		return fmt.Errorf("could not count projects: %w", err)
+	}
+
+	templates, err := config.client.TemplateProjects(config.opts.owner)
+	if err != nil {
+		return fmt.Errorf("could not list template projects: %w", err)
+	}
+
+	stats := templateStats{
+		Owner:         config.opts.owner,
+		TotalProjects: projects.TotalCount,
+		TemplateCount: templates.TotalCount,
+		TemplateRatio: ratio(templates.TotalCount, projects.TotalCount),
+	}
+
+	caps, err := config.client.ProbeTemplateCapabilities()
+	if err != nil {
+		return fmt.Errorf("could not probe template capabilities: %w", err)
+	}
+	stats.DerivativesSupported = caps.Derivatives
+
+	if stats.DerivativesSupported {
+		stats.Templates = make([]templateDerivativeStat, 0, len(templates.Nodes))
+		for _, t := range templates.Nodes {
+			derivatives, err := config.client.DerivedProjects(config.opts.owner, t.Number)
+			if err != nil {
+				return fmt.Errorf("could not count derivatives of project %d: %w", t.Number, err)
+			}
+			stats.Templates = append(stats.Templates, templateDerivativeStat{
+				Number:      t.Number,
+				Title:       t.Title,
+				Derivatives: derivatives.TotalCount,
+			})
+			stats.TotalDerivatives += derivatives.TotalCount
+		}
+	}
+
+	if config.opts.exporter != nil {
+		return config.opts.exporter.Write(config.io, stats)
+	}
+
+	return printStats(config, stats)
+}
+
+// ratio returns n/total as a fraction rounded to two decimal places, or 0 if
+// total is 0, so an org with no projects reports a coverage of 0 instead of
+// dividing by zero.
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(int(float64(n)/float64(total)*10000+0.5)) / 10000
+}
+
+func printStats(config statsConfig, stats templateStats) error {
+	_, err := fmt.Fprintf(config.io.Out, "%d of %d projects are templates (%.2f%%)\n",
+		stats.TemplateCount, stats.TotalProjects, stats.TemplateRatio*100)
+	if err != nil {
+		return err
+	}
+
+	if !stats.DerivativesSupported {
+		_, err := fmt.Fprintln(config.io.Out, "derivative counts are not available on this host")
+		return err
+	}
+
+	if len(stats.Templates) == 0 {
+		return nil
+	}
+
+	tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title", "Derivatives"))
+	for _, t := range stats.Templates {
+		tp.AddField(strconv.Itoa(int(t.Number)), tableprinter.WithTruncate(nil))
+		tp.AddField(t.Title)
+		tp.AddField(strconv.Itoa(t.Derivatives))
+		tp.EndRow()
+	}
+	return tp.Render()
+}
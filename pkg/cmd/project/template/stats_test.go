@@ -0,0 +1,167 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRunStats(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectsV2": map[string]interface{}{
+						"totalCount": 4,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes": []interface{}{
+							map[string]interface{}{"title": "Roadmap", "id": "roadmap ID", "number": 1},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login": "github",
+					"projectV2Templates": map[string]interface{}{
+						"totalCount": 1,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false, "endCursor": ""},
+						"nodes": []interface{}{
+							map[string]interface{}{
+								"number":    1,
+								"title":     "Roadmap",
+								"url":       "https://github.com/orgs/github/projects/1",
+								"public":    true,
+								"updatedAt": "2024-01-02T15:04:05Z",
+								"items":     map[string]interface{}{"totalCount": 3},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"name": "derivedProjects"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectDerivatives.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"derivedProjects": map[string]interface{}{
+							"totalCount": 3,
+							"pageInfo":   map[string]interface{}{"hasNextPage": false},
+							"nodes":      []interface{}{},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	config := statsConfig{
+		opts:   statsOpts{owner: "github"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runStats(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "1 of 4 projects are templates (25.00%)")
+	assert.Contains(t, stdout.String(), "Roadmap")
+	assert.Contains(t, stdout.String(), "3")
+}
+
+func TestRunStats_DerivativesUnsupported(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjects.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectsV2": map[string]interface{}{
+						"totalCount": 0,
+						"pageInfo":   map[string]interface{}{"hasNextPage": false},
+						"nodes":      []interface{}{},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query OrgProjectTemplates.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"login":               "github",
+					"projectV2Templates": map[string]interface{}{"totalCount": 0, "pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": ""}, "nodes": []interface{}{}},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2":    map[string]interface{}{"fields": []interface{}{}},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	config := statsConfig{
+		opts:   statsOpts{owner: "github"},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runStats(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "0 of 0 projects are templates (0.00%)")
+	assert.Contains(t, stdout.String(), "derivative counts are not available on this host")
+	assert.False(t, gock.HasUnmatchedRequest())
+}
@@ -0,0 +1,53 @@
+package template
+
+import (
+	"encoding/json"
+)
+
+// jsonlItem is one line of a --jsonl stream, emitted for each --from-file
+// manifest entry as soon as it has been processed.
+type jsonlItem struct {
+	Type   string    `json:"type"`
+	Owner  string    `json:"owner"`
+	Number int32     `json:"number"`
+	Action string    `json:"action"`
+	Error  string    `json:"error,omitempty"`
+	Code   errorCode `json:"code,omitempty"`
+}
+
+// jsonlSummary is the final line of a --jsonl stream, marking it complete
+// and carrying the same aggregate counts as batchSummary.
+type jsonlSummary struct {
+	Type string `json:"type"`
+	batchSummary
+}
+
+// writeJSONLItem writes one --jsonl item line for entry, classifying it the
+// same way runManifest classifies entries into batchSummary buckets.
+func writeJSONLItem(config templateConfig, entry manifestEntry, outcome entryOutcome) error {
+	item := jsonlItem{Type: "item", Owner: entry.Owner, Number: entry.Number}
+
+	switch {
+	case outcome.err != nil:
+		item.Action = "failed"
+		item.Error = outcome.err.Error()
+		item.Code = classifyError(outcome.err)
+	case outcome.noop:
+		item.Action = "noop"
+	case entry.Undo:
+		item.Action = "unmarked"
+	default:
+		item.Action = "marked"
+	}
+
+	enc := json.NewEncoder(config.io.Out)
+	return enc.Encode(item)
+}
+
+// writeJSONLSummary writes the final --jsonl line, so a consumer reading the
+// stream incrementally can tell it has ended and read the aggregate counts
+// without needing a separate --summary-format request.
+func writeJSONLSummary(config templateConfig, summary batchSummary) error {
+	enc := json.NewEncoder(config.io.Out)
+	return enc.Encode(jsonlSummary{Type: "summary", batchSummary: summary})
+}
@@ -0,0 +1,1295 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/gh"
+	"github.com/cli/cli/v2/internal/prompter"
+	"github.com/cli/cli/v2/internal/tableprinter"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/client"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	shellquote "github.com/kballard/go-shellquote"
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+)
+
+// defaultVerifyInterval is the wait between verification read attempts. It is
+// deliberately a few seconds, long enough to ride out typical replication
+// lag on a read-after-write without making `--verify` noticeably slow for
+// the common case where the first read already succeeds.
+const defaultVerifyInterval = 2 * time.Second
+
+// defaultMaxBackoff caps how long any single --verify retry delay can grow
+// to, regardless of how many retries have already happened, so a long
+// --verify-retries run never sleeps for minutes at a stretch.
+const defaultMaxBackoff = 30 * time.Second
+
+type templateOpts struct {
+	owner               string
+	undo                bool
+	description         string
+	number              int32
+	projectID           string
+	exporter            cmdutil.Exporter
+	capabilities        bool
+	quietErrors         bool
+	outputNull          bool
+	shellExport         bool
+	summaryFormat       string
+	verify              bool
+	verifyRetries       int
+	verifyInterval      time.Duration
+	maxBackoff          time.Duration
+	verbose             bool
+	sleep               func(time.Duration)
+	fromFile            string
+	viewerCache         string
+	viewerMemo          *viewerLoginMemo
+	viewerCacheDir      string
+	metricsFile         string
+	allOrgs             bool
+	myOrgs              bool
+	title               string
+	yes                 bool
+	descriptionContains string
+	fieldOption         string
+	confirmThreshold    int
+	maxAffected         int
+	linkRepo            string
+	retryLog            string
+	now                 func() time.Time
+	fieldValue          string
+	restoreState        string
+	dryRun              bool
+	output              string
+	enterprise          string
+	seed                int64
+	rng                 *rand.Rand
+	concurrency         int
+	ownerConcurrency    int
+	waitForRateLimit    bool
+	jsonl               bool
+	indent              int
+	id                  string
+	timeout             time.Duration
+	timeoutPerRetry     time.Duration
+	rangeExpr           string
+	includeMeta         bool
+	gzip                bool
+	search              string
+	allowWindow         string
+	force               bool
+	preHook             string
+	postHook            string
+	groupByOwner        bool
+	validateOnly        bool
+	repo                string
+	projectNumber       int32
+	allLinked           bool
+	noAnnotations       bool
+	quiet               bool
+	diffAgainst         string
+	noFailOnDrift       bool
+	templateName        string
+	nameMap             string
+	operationLog        string
+	undoLog             string
+}
+
+type templateConfig struct {
+	client     *queries.Client
+	opts       templateOpts
+	io         *iostreams.IOStreams
+	prompter   prompter.Prompter
+	httpClient func() (*http.Client, error)
+	cfg        func() (gh.Config, error)
+}
+
+type markProjectTemplateMutation struct {
+	TemplateProject struct {
+		Project queries.Project `graphql:"projectV2"`
+	} `graphql:"markProjectV2AsTemplate(input:$input)"`
+}
+type unmarkProjectTemplateMutation struct {
+	TemplateProject struct {
+		Project queries.Project `graphql:"projectV2"`
+	} `graphql:"unmarkProjectV2AsTemplate(input:$input)"`
+}
+
+type updateTemplateDescriptionMutation struct {
+	UpdateProjectV2 struct {
+		Project queries.Project `graphql:"projectV2"`
+	} `graphql:"updateProjectV2(input:$input)"`
+}
+
+// parseProjectNumber parses the project number positional argument,
+// tolerating the stray thousands separators and surrounding whitespace that
+// show up when a number is copy-pasted from a spreadsheet or URL. It rejects
+// non-ASCII digits explicitly, since strconv.ParseInt's "invalid syntax"
+// error gives no hint as to why a seemingly-numeric string failed to parse.
+func parseProjectNumber(raw string) (int32, error) {
+	cleaned := strings.TrimSpace(raw)
+	cleaned = strings.ReplaceAll(cleaned, ",", "")
+	cleaned = strings.ReplaceAll(cleaned, " ", "")
+
+	for _, r := range cleaned {
+		if unicode.IsDigit(r) && (r < '0' || r > '9') {
+			return 0, fmt.Errorf("invalid number %q: non-ASCII digits are not supported", raw)
+		}
+	}
+
+	num, err := strconv.ParseInt(cleaned, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %v", raw)
+	}
+	return int32(num), nil
+}
+
+func NewCmdTemplate(f *cmdutil.Factory, runF func(config templateConfig) error) *cobra.Command {
+	opts := templateOpts{sleep: time.Sleep, now: time.Now, viewerMemo: &viewerLoginMemo{}, concurrency: 1, ownerConcurrency: 2}
+	templateCmd := &cobra.Command{
+		Short: "Manage project templates",
+		Use:   "template [<number>]",
+		Example: heredoc.Doc(`
+			# mark the github org's project "1" as a template
+			gh project template 1 --owner "github"
+
+			# mark it as a template and set its description in one call
+			gh project template 1 --owner "github" --description "Start here for new roadmaps"
+
+			# unmark the github org's project "1" as a template
+			gh project template 1 --owner "github" --undo
+
+			# print the template-related capabilities of the current host
+			gh project template --capabilities
+
+			# mark as a template and confirm the project is still reachable on a follow-up read
+			gh project template 1 --owner "github" --verify
+
+			# mark or unmark a batch of projects described by a JSON manifest
+			gh project template --from-file manifest.json
+
+			# lint a manifest in CI without marking or unmarking anything
+			gh project template --from-file manifest.json --validate-only
+
+			# in an Actions workflow, mark the repo's org's project "5" as a template
+			gh project template --repo ${{ github.repository }} --project-number 5
+
+			# mark the project titled "Roadmap" as a template in every org you belong to
+			gh project template --all-orgs --title "Roadmap" --yes
+
+			# same, but across every org you're a member of, not just the ones you can write to
+			gh project template --my-orgs --title "Roadmap" --yes
+
+			# mark every project in the github org whose description mentions "[template]"
+			gh project template --owner "github" --description-contains "[template]"
+
+			# mark the project as a template and link it to a showcase repo in one step
+			gh project template 1 --owner "github" --link-repo "github/showcase"
+
+			# mark every project in the github org tagged with an external ID
+			gh project template --owner "github" --field-value "ExternalID=PRJ-42" --yes
+
+			# restore a dumped snapshot of template flags, previewing the changes first
+			gh project template --restore-state state.json --dry-run
+			gh project template --restore-state state.json --yes
+
+			# wait out rate-limit exhaustion instead of failing during heavy batch usage
+			gh project template --from-file manifest.json --wait-for-ratelimit
+
+			# keep the normal terminal output, and also capture JSON results to a file
+			gh project template --owner github --description-contains archived --output results.json
+
+			# mark a matching project as a template across every org in an enterprise
+			gh project template --enterprise my-enterprise --title "Roadmap"
+
+			# process a manifest 5 entries at a time overall, but never more than 2 at once
+			# against any single owner, to stay under GitHub's per-account abuse limits
+			gh project template --from-file manifest.json --concurrency 5 --owner-concurrency 2
+
+			# stream per-entry results as they complete, ending with a summary line
+			gh project template --from-file manifest.json --jsonl
+
+			# mark a project as a template by its GraphQL node ID, e.g. from a prior --output dump
+			gh project template --id "PVT_kwDOA..."
+
+			# bound each verification attempt to 5s, and the whole retry sequence to 30s
+			gh project template 1 --owner "github" --verify --timeout-per-retry 5s --timeout 30s
+
+			# mark projects 1 through 10 in the github org as templates
+			gh project template --range 1-10 --owner "github"
+
+			# record which gh version and commit produced this --output dump
+			gh project template 1 --owner "github" --output result.json --include-meta
+
+			# mark every project matched by a saved GitHub search query as a template
+			gh project template --search "org:github is:open" --yes
+
+			# only allow a bulk operation during the weekday business-hours maintenance window
+			gh project template --from-file manifest.json --allow-window "Mon-Fri 09:00-17:00 America/New_York"
+
+			# override the maintenance window for an urgent bulk change
+			gh project template --from-file manifest.json --allow-window "Mon-Fri 09:00-17:00 America/New_York" --force
+
+			# notify a channel before and after marking the project as a template
+			gh project template 1 --owner "github" \
+				--pre-hook 'notify-slack.sh "about to $GH_TEMPLATE_ACTION project $GH_PROJECT_NUMBER"' \
+				--post-hook 'notify-slack.sh "$GH_TEMPLATE_ACTION complete for project $GH_PROJECT_NUMBER"'
+
+			# group --all-orgs results by owner instead of a flat array
+			gh project template --all-orgs --title "Roadmap" --group-by-owner
+
+			# check for drift against a baseline snapshot, without changing anything
+			gh project template --diff-against baseline-state.json
+
+			# mark the project named "Standard Roadmap" in names.yml as a template,
+			# without hardcoding its number
+			gh project template --owner github --template-name "Standard Roadmap" --name-map names.yml
+
+			# record every mutation from a batch run, so it can be undone later
+			gh project template --from-file manifest.json --operation-log ops.jsonl
+
+			# preview, then replay, the inverse of that recorded batch
+			gh project template --undo-log ops.jsonl --dry-run
+			gh project template --undo-log ops.jsonl --yes
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := client.New(f)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				num, err := parseProjectNumber(args[0])
+				if err != nil {
+					return cmdutil.FlagErrorf("%s", err)
+				}
+				opts.number = num
+			}
+
+			if opts.fromFile != "" && (opts.number != 0 || opts.owner != "" || opts.undo) {
+				return cmdutil.FlagErrorf("specify either a project number or --from-file, not both")
+			}
+
+			if opts.templateName != "" {
+				if opts.nameMap == "" {
+					return cmdutil.FlagErrorf("--template-name requires --name-map")
+				}
+				if opts.number != 0 {
+					return cmdutil.FlagErrorf("--template-name cannot be combined with a project number")
+				}
+
+				number, err := resolveTemplateName(opts.templateName, opts.nameMap, f.IOStreams.In)
+				if err != nil {
+					return err
+				}
+				opts.number = number
+			} else if opts.nameMap != "" {
+				return cmdutil.FlagErrorf("--name-map requires --template-name")
+			}
+
+			if opts.owner == "*" {
+				opts.allOrgs = true
+				opts.owner = ""
+			}
+			if opts.allOrgs {
+				if opts.number == 0 && opts.title == "" {
+					return cmdutil.FlagErrorf("--all-orgs requires a project number or --title to match against")
+				}
+				if opts.fromFile != "" {
+					return cmdutil.FlagErrorf("--all-orgs cannot be combined with --from-file")
+				}
+			}
+
+			if opts.myOrgs {
+				if opts.owner != "" || opts.allOrgs || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.fromFile != "" || opts.enterprise != "" {
+					return cmdutil.FlagErrorf("--my-orgs cannot be combined with --owner, --all-orgs, --description-contains, --field-value, --field-option, --restore-state, --from-file, or --enterprise")
+				}
+				if opts.number == 0 && opts.title == "" {
+					return cmdutil.FlagErrorf("--my-orgs requires a project number or --title to match against")
+				}
+			}
+
+			if opts.linkRepo != "" {
+				if opts.undo {
+					return cmdutil.FlagErrorf("--link-repo cannot be used with --undo")
+				}
+				if !strings.Contains(opts.linkRepo, "/") {
+					return cmdutil.FlagErrorf("--link-repo must be in the \"OWNER/REPO\" format, got %q", opts.linkRepo)
+				}
+			}
+
+			if opts.repo != "" {
+				if opts.owner != "" || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.fromFile != "" || opts.id != "" || opts.restoreState != "" {
+					return cmdutil.FlagErrorf("--repo cannot be combined with --owner, --all-orgs, --my-orgs, --enterprise, --from-file, --id, or --restore-state")
+				}
+				if !strings.Contains(opts.repo, "/") {
+					return cmdutil.FlagErrorf("--repo must be in the \"OWNER/REPO\" format, got %q", opts.repo)
+				}
+				if opts.allLinked {
+					if opts.number != 0 || opts.projectNumber != 0 {
+						return cmdutil.FlagErrorf("--all-linked cannot be combined with a project number or --project-number")
+					}
+				} else {
+					if opts.number != 0 {
+						return cmdutil.FlagErrorf("--repo requires --project-number instead of a project number argument")
+					}
+					if opts.projectNumber == 0 {
+						return cmdutil.FlagErrorf("--repo requires --project-number")
+					}
+					opts.number = opts.projectNumber
+				}
+			} else if opts.projectNumber != 0 {
+				return cmdutil.FlagErrorf("--project-number requires --repo")
+			} else if opts.allLinked {
+				return cmdutil.FlagErrorf("--all-linked requires --repo")
+			}
+
+			if opts.allLinked {
+				if opts.title != "" || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.search != "" || opts.rangeExpr != "" {
+					return cmdutil.FlagErrorf("--all-linked cannot be combined with --title, --description-contains, --field-value, --field-option, --search, or --range")
+				}
+				if opts.verify {
+					return cmdutil.FlagErrorf("--all-linked cannot be combined with --verify, since matches can span projects with no single owner and number to re-read by")
+				}
+			}
+
+			if opts.retryLog != "" {
+				if err := validateRetryLogPath(opts.retryLog); err != nil {
+					return cmdutil.FlagErrorf("--retry-log %q is not writable: %s", opts.retryLog, err)
+				}
+			}
+
+			if opts.operationLog != "" {
+				if err := validateOperationLogPath(opts.operationLog); err != nil {
+					return cmdutil.FlagErrorf("--operation-log %q is not writable: %s", opts.operationLog, err)
+				}
+			}
+
+			if opts.undoLog != "" {
+				if opts.number != 0 || opts.owner != "" || opts.undo || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.diffAgainst != "" || opts.fromFile != "" || opts.rangeExpr != "" || opts.search != "" || opts.id != "" || opts.templateName != "" || opts.allLinked {
+					return cmdutil.FlagErrorf("--undo-log cannot be combined with a project number, --owner, --undo, --all-orgs, --my-orgs, --enterprise, --description-contains, --field-value, --field-option, --restore-state, --diff-against, --from-file, --range, --search, --id, --template-name, or --all-linked")
+				}
+			}
+
+			if opts.descriptionContains != "" {
+				if opts.number != 0 || opts.undo || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.diffAgainst != "" || opts.fromFile != "" || opts.rangeExpr != "" || opts.search != "" || opts.id != "" || opts.undoLog != "" || opts.allLinked {
+					return cmdutil.FlagErrorf("--description-contains cannot be combined with a project number, --undo, --all-orgs, --my-orgs, --enterprise, --field-value, --field-option, --restore-state, --diff-against, --from-file, --range, --search, --id, --undo-log, or --all-linked")
+				}
+			}
+
+			if opts.fieldValue != "" {
+				if opts.number != 0 || opts.allOrgs || opts.descriptionContains != "" || opts.myOrgs || opts.fieldOption != "" || opts.fromFile != "" {
+					return cmdutil.FlagErrorf("--field-value cannot be combined with a project number, --all-orgs, --my-orgs, --field-option, --description-contains, or --from-file")
+				}
+				if _, _, err := parseFieldValue(opts.fieldValue); err != nil {
+					return cmdutil.FlagErrorf("--field-value %s", err)
+				}
+			}
+
+			if opts.fieldOption != "" {
+				if opts.number != 0 || opts.allOrgs || opts.descriptionContains != "" || opts.myOrgs || opts.fromFile != "" {
+					return cmdutil.FlagErrorf("--field-option cannot be combined with a project number, --all-orgs, --my-orgs, --description-contains, or --from-file")
+				}
+				if _, _, err := parseFieldOption(opts.fieldOption); err != nil {
+					return cmdutil.FlagErrorf("--field-option %s", err)
+				}
+			}
+
+			if opts.restoreState != "" {
+				if opts.number != 0 || opts.owner != "" || opts.undo || opts.allOrgs || opts.myOrgs || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.fromFile != "" {
+					return cmdutil.FlagErrorf("--restore-state cannot be combined with a project number, --owner, --undo, --all-orgs, --my-orgs, --description-contains, --field-value, --field-option, or --from-file")
+				}
+			} else if opts.dryRun && opts.undoLog == "" {
+				return cmdutil.FlagErrorf("--dry-run is only meaningful with --restore-state or --undo-log")
+			}
+
+			if opts.diffAgainst != "" {
+				if opts.number != 0 || opts.owner != "" || opts.undo || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.fromFile != "" || opts.verify {
+					return cmdutil.FlagErrorf("--diff-against cannot be combined with a project number, --owner, --undo, --all-orgs, --my-orgs, --enterprise, --description-contains, --field-value, --field-option, --restore-state, --from-file, or --verify")
+				}
+			} else if opts.noFailOnDrift {
+				return cmdutil.FlagErrorf("--no-fail-on-drift requires --diff-against")
+			}
+
+			if opts.enterprise != "" {
+				if opts.owner != "" || opts.allOrgs || opts.myOrgs || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.fromFile != "" {
+					return cmdutil.FlagErrorf("--enterprise cannot be combined with --owner, --all-orgs, --my-orgs, --description-contains, --field-value, --field-option, --restore-state, or --from-file")
+				}
+				if opts.number == 0 && opts.title == "" {
+					return cmdutil.FlagErrorf("--enterprise requires a project number or --title to match against")
+				}
+			}
+
+			if opts.concurrency < 1 {
+				return cmdutil.FlagErrorf("--concurrency must be at least 1")
+			}
+			if opts.ownerConcurrency < 1 {
+				return cmdutil.FlagErrorf("--owner-concurrency must be at least 1")
+			}
+
+			if opts.jsonl && opts.fromFile == "" {
+				return cmdutil.FlagErrorf("--jsonl requires --from-file")
+			}
+
+			if opts.indent < 0 || opts.indent > 8 {
+				return cmdutil.FlagErrorf("--indent must be between 0 and 8")
+			}
+
+			if opts.id != "" {
+				if opts.number != 0 || opts.owner != "" || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.fromFile != "" {
+					return cmdutil.FlagErrorf("--id cannot be combined with a project number, --owner, --all-orgs, --my-orgs, --enterprise, --description-contains, --field-value, --field-option, --restore-state, or --from-file")
+				}
+				if opts.verify {
+					return cmdutil.FlagErrorf("--id cannot be combined with --verify, since verifying re-reads the project by owner and number rather than by ID")
+				}
+			}
+
+			if opts.timeoutPerRetry > 0 && opts.timeout > 0 && opts.timeoutPerRetry > opts.timeout {
+				return cmdutil.FlagErrorf("--timeout-per-retry cannot exceed --timeout")
+			}
+
+			if opts.search != "" {
+				if opts.number != 0 || opts.owner != "" || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.fromFile != "" || opts.id != "" || opts.rangeExpr != "" {
+					return cmdutil.FlagErrorf("--search cannot be combined with a project number, --owner, --all-orgs, --my-orgs, --enterprise, --description-contains, --field-value, --field-option, --restore-state, --from-file, --id, or --range")
+				}
+				if opts.verify {
+					return cmdutil.FlagErrorf("--search cannot be combined with --verify, since matches can span projects with no single owner and number to re-read by")
+				}
+			}
+
+			if opts.includeMeta && opts.output == "" {
+				return cmdutil.FlagErrorf("--include-meta requires --output")
+			}
+
+			if opts.gzip && opts.output == "" {
+				return cmdutil.FlagErrorf("--gzip requires --output")
+			}
+
+			if opts.rangeExpr != "" {
+				if opts.number != 0 || opts.allOrgs || opts.myOrgs || opts.enterprise != "" || opts.descriptionContains != "" || opts.fieldValue != "" || opts.fieldOption != "" || opts.restoreState != "" || opts.fromFile != "" || opts.id != "" {
+					return cmdutil.FlagErrorf("--range cannot be combined with a project number, --all-orgs, --my-orgs, --enterprise, --description-contains, --field-value, --field-option, --restore-state, --from-file, or --id")
+				}
+				if opts.owner == "" {
+					return cmdutil.FlagErrorf("--range requires --owner")
+				}
+				if _, err := parseRange(opts.rangeExpr); err != nil {
+					return cmdutil.FlagErrorf("%s", err)
+				}
+			}
+
+			if opts.allowWindow != "" {
+				if _, err := parseAllowWindow(opts.allowWindow); err != nil {
+					return cmdutil.FlagErrorf("%s", err)
+				}
+			}
+
+			if opts.groupByOwner && !isMultiOwnerOperation(opts) {
+				return cmdutil.FlagErrorf("--group-by-owner requires --from-file, --range, --all-orgs, --my-orgs, --enterprise, --search, or --all-linked")
+			}
+
+			if opts.validateOnly && opts.fromFile == "" {
+				return cmdutil.FlagErrorf("--validate-only requires --from-file")
+			}
+
+			if opts.shellExport {
+				if isBulkOperation(opts) {
+					return cmdutil.FlagErrorf("--shell-export is only supported for a single-project operation")
+				}
+				if opts.exporter != nil {
+					return cmdutil.FlagErrorf("--shell-export cannot be combined with --format")
+				}
+			}
+
+			seed := resolveSeed(opts.seed, cmd.Flags().Changed("seed"), os.Getenv("GH_PROJECT_SEED"))
+			opts.rng = rand.New(rand.NewSource(seed))
+
+			config := templateConfig{
+				client:     client,
+				opts:       opts,
+				io:         f.IOStreams,
+				prompter:   f.Prompter,
+				httpClient: f.HttpClient,
+				cfg:        f.Config,
+			}
+
+			// allow testing of the command without actually running it
+			if runF != nil {
+				return runF(config)
+			}
+
+			var runErr error
+			if opts.capabilities {
+				runErr = runCapabilities(config)
+			} else {
+				runErr = runTemplate(config)
+			}
+			if runErr != nil {
+				if annErr := printErrorAnnotation(config, runErr); annErr != nil {
+					return annErr
+				}
+			}
+			return runErr
+		},
+	}
+
+	templateCmd.Flags().StringVar(&opts.owner, "owner", "", "Login of the org owner.")
+	templateCmd.Flags().BoolVar(&opts.undo, "undo", false, "Unmark the project as a template.")
+	templateCmd.Flags().StringVar(&opts.description, "description", "", "Also set the project's short description when marking it as a template. Ignored with --undo, which leaves the description unchanged.")
+	templateCmd.Flags().BoolVar(&opts.capabilities, "capabilities", false, "Print the template-related capabilities of the current host as JSON and exit.")
+	templateCmd.Flags().BoolVar(&opts.quietErrors, "quiet-errors", false, "Suppress non-fatal warnings on stderr. Fatal errors are still printed.")
+	templateCmd.Flags().BoolVar(&opts.outputNull, "output-null", false, "Print the project URL followed by a NUL byte instead of a human-readable message, for safe use with xargs -0.")
+	templateCmd.Flags().BoolVar(&opts.shellExport, "shell-export", false, "Print GH_PROJECT_ID and GH_PROJECT_NUMBER as shell-quoted assignments instead of a human-readable message, for `eval \"$(gh project template ... --shell-export)\"`. Only supported for a single-project operation.")
+	cmdutil.StringEnumFlag(templateCmd, &opts.summaryFormat, "summary-format", "", "text", []string{"text", "json"}, "Format of the batch tail summary printed to stderr")
+	templateCmd.Flags().BoolVar(&opts.verify, "verify", false, "Read the project back after the mutation and confirm it's still reachable under the same ID, retrying to ride out eventual-consistency lag. Off by default: the mutation response is trusted as-is, so a simple run doesn't pay for an extra round-trip. Note: the project's template flag can't be read back (not exposed by the API for GHES 3.8 compatibility), so this confirms the project wasn't lost or replaced, not that the flag change landed.")
+	templateCmd.Flags().IntVar(&opts.verifyRetries, "verify-retries", 3, "Number of additional verification reads to attempt if the first one errors or returns a different project.")
+	templateCmd.Flags().DurationVar(&opts.verifyInterval, "verify-interval", defaultVerifyInterval, "Base delay before the first verification retry, e.g. \"2s\", \"500ms\". Later retries back off exponentially from this, up to --max-backoff.")
+	templateCmd.Flags().DurationVar(&opts.maxBackoff, "max-backoff", defaultMaxBackoff, "Upper bound on the exponentially-growing delay between --verify retries, so a long --verify-retries run never sleeps for minutes at a stretch. 0 disables the cap.")
+	templateCmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "Print additional detail, such as how many verification attempts were needed.")
+	templateCmd.Flags().StringVarP(&opts.fromFile, "from-file", "F", "", "Process a batch of projects described by a JSON array read from `file` (use \"-\" to read from standard input). Each entry takes the same owner, number, and undo fields as the single-project form.")
+	cmdutil.StringEnumFlag(templateCmd, &opts.viewerCache, "viewer-cache", "", "memory", []string{"none", "memory", "disk"}, "How to cache the @me viewer lookup used when --owner is omitted: none queries every time, memory caches for this run, disk additionally persists for 1h")
+	templateCmd.Flags().StringVar(&opts.metricsFile, "metrics-file", "", "Write Prometheus textfile-format metrics for this run to `path` after it completes, for node_exporter's textfile collector.")
+	templateCmd.Flags().BoolVar(&opts.allOrgs, "all-orgs", false, "Apply the template operation to a matching project in every organization the viewer belongs to. Equivalent to --owner '*'. Requires --title or a project number, and is subject to --confirm-threshold.")
+	templateCmd.Flags().BoolVar(&opts.myOrgs, "my-orgs", false, "Apply the template operation to a matching project in every organization the viewer is a member of, including orgs the viewer cannot write to (unlike --all-orgs). A write operation against an org the viewer lacks permission for fails just that org rather than the whole run. Requires --title or a project number, and is subject to --confirm-threshold.")
+	templateCmd.Flags().StringVar(&opts.title, "title", "", "Match the project to operate on by title instead of by number. Required by --all-orgs unless a project number is given.")
+	templateCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip the confirmation prompt before a bulk operation that exceeds --confirm-threshold.")
+	templateCmd.Flags().StringVar(&opts.descriptionContains, "description-contains", "", "Template every project belonging to --owner whose short description contains this `marker`, instead of operating on a single project by number. Subject to --confirm-threshold.")
+	templateCmd.Flags().IntVar(&opts.confirmThreshold, "confirm-threshold", 10, "Require extra confirmation before a bulk operation (--all-orgs, --my-orgs, --description-contains) affects more than this many projects. Set to 0 to disable the guard entirely.")
+	templateCmd.Flags().IntVar(&opts.maxAffected, "max-affected", 0, "Hard-stop any bulk operation (--from-file, --range, --all-orgs, --my-orgs, --enterprise, --search, --all-linked, --description-contains, --field-value, --field-option, --restore-state, --undo-log) before mutating anything if the selected set exceeds this many projects. Unlike --confirm-threshold, this cannot be bypassed with --yes: it's a safety net against a mis-scoped selector, not a confirmation. 0 (the default) disables the guard.")
+	templateCmd.Flags().StringVar(&opts.linkRepo, "link-repo", "", "After marking the project as a template, link it to the repository `owner/name`. Not compatible with --undo.")
+	templateCmd.Flags().StringVar(&opts.retryLog, "retry-log", "", "Append a JSON-lines record of each --verify retry attempt (timestamp, error, backoff) to `path`, for analyzing flakiness offline.")
+	templateCmd.Flags().StringVar(&opts.fieldValue, "field-value", "", "Template every project belonging to --owner whose `FIELD=VALUE` matches an item's field value, for orgs that tag projects with an external system ID. Resolving field values is expensive, so this always requires confirmation or --yes regardless of --confirm-threshold.")
+	templateCmd.Flags().StringVar(&opts.fieldOption, "field-option", "", "Template every project belonging to --owner where an item's `FIELD` single-select field is set to the named option, for orgs that categorize projects via a single-select field instead of a free-text value. Resolving field options is expensive, so this always requires confirmation or --yes regardless of --confirm-threshold.")
+	templateCmd.Flags().StringVar(&opts.restoreState, "restore-state", "", "Restore the template flags recorded in the `file` written by `template audit --dump-state`, marking or unmarking each project to match and skipping any already in the recorded state. Always requires confirmation or --yes.")
+	templateCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "With --restore-state, print the changes that would be made without applying them.")
+	templateCmd.Flags().BoolVar(&opts.waitForRateLimit, "wait-for-ratelimit", false, "If the primary GraphQL rate limit is exhausted, sleep until it resets instead of exiting with an error.")
+	templateCmd.Flags().StringVar(&opts.output, "output", "", "Also write the result as JSON to `file`, independent of what --format and the terminal show. Unlike --format, this never suppresses the human-readable output.")
+	templateCmd.Flags().StringVar(&opts.enterprise, "enterprise", "", "Apply to a matching project across every organization belonging to the enterprise `slug`, for fleet-wide template governance. Requires a project number or --title, and errors clearly if the host's GraphQL schema does not support enterprise-scoped queries. Subject to --confirm-threshold.")
+	templateCmd.Flags().Int64Var(&opts.seed, "seed", 0, "Seed the RNG used to jitter --verify retry backoff, for reproducing an identical backoff sequence in tests and debugging. Defaults to a time-based seed; also settable via GH_PROJECT_SEED.")
+	_ = templateCmd.Flags().MarkHidden("seed")
+	templateCmd.Flags().IntVar(&opts.concurrency, "concurrency", 1, "Number of --from-file manifest entries to process in parallel.")
+	templateCmd.Flags().IntVar(&opts.ownerConcurrency, "owner-concurrency", 2, "Maximum number of --from-file manifest entries for the same --owner to process at once, independent of --concurrency, so a --concurrency that spans many entries for one owner doesn't trip GitHub's per-account abuse-detection limits.")
+	templateCmd.Flags().BoolVar(&opts.jsonl, "jsonl", false, "With --from-file, stream one JSON Lines object per processed entry (\"type\":\"item\") to standard output as it completes, followed by a final (\"type\":\"summary\") line once the whole batch is done. Makes the stream self-terminating for consumers reading it incrementally.")
+	templateCmd.Flags().IntVar(&opts.indent, "indent", 2, "Number of spaces to indent pretty-printed JSON written by --capabilities and --output. Must be between 0 and 8.")
+	templateCmd.Flags().StringVar(&opts.id, "id", "", "Operate on the project with this GraphQL node ID directly, skipping owner+number resolution. Not compatible with a project number, --owner, or --verify.")
+	templateCmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Overall deadline for --verify's retries combined, e.g. \"30s\". A slow attempt is canceled and retried within whatever budget remains. 0 means no overall deadline.")
+	templateCmd.Flags().DurationVar(&opts.timeoutPerRetry, "timeout-per-retry", 0, "Deadline for each individual --verify attempt, distinct from --timeout's deadline for the operation as a whole. 0 means no per-attempt deadline.")
+	templateCmd.Flags().StringVar(&opts.rangeExpr, "range", "", "Expand an inclusive \"<start>-<end>\" range, e.g. \"1-10\", into that many project numbers belonging to --owner, processed the same way --from-file is. Subject to --confirm-threshold.")
+	templateCmd.Flags().BoolVar(&opts.includeMeta, "include-meta", false, "Wrap --output's JSON in a {\"meta\", \"data\"} envelope recording the CLI version and commit that produced it, for tracing an audit record back to its source binary. Requires --output.")
+	templateCmd.Flags().BoolVar(&opts.gzip, "gzip", false, "Gzip-compress the file written by --output, appending \".gz\" to its name if not already present. Requires --output.")
+	templateCmd.Flags().StringVar(&opts.search, "search", "", "Template every project matched by this GitHub search `query`, run with type: PROJECT. Requires the host's GraphQL schema to support PROJECT-scoped search, and always requires confirmation or --yes regardless of --confirm-threshold.")
+	templateCmd.Flags().StringVar(&opts.allowWindow, "allow-window", "", "Refuse a bulk operation (--from-file, --range, --search, --all-orgs, --my-orgs, --enterprise, --description-contains, --field-value, --field-option, --restore-state, --undo-log, --all-linked) outside this maintenance window, for change-management discipline. Format: \"<start day>-<end day> <start time>-<end time> <zoneinfo location>\", e.g. \"Mon-Fri 09:00-17:00 America/New_York\". Days are 3-letter abbreviations; times are 24-hour \"HH:MM\"; both ranges may wrap around. Single-project operations are never affected. Overridable with --force.")
+	templateCmd.Flags().BoolVar(&opts.force, "force", false, "Run a bulk operation even outside --allow-window.")
+	templateCmd.Flags().StringVar(&opts.preHook, "pre-hook", "", "Shell `command` to run before each template mutation, with GH_PROJECT_NUMBER, GH_PROJECT_ID, GH_PROJECT_OWNER, and GH_TEMPLATE_ACTION set in its environment. A failing pre-hook aborts that mutation. Runs with gh's own privileges: never set this to unsanitized input.")
+	templateCmd.Flags().StringVar(&opts.postHook, "post-hook", "", "Shell `command` to run after each successful template mutation, with the same environment as --pre-hook. A failing post-hook only warns; the mutation it ran after has already succeeded. Runs with gh's own privileges: never set this to unsanitized input.")
+	templateCmd.Flags().BoolVar(&opts.groupByOwner, "group-by-owner", false, "Structure JSON/--output results as an object keyed by owner login instead of a flat array, and print a per-owner section on a TTY. Requires a batch operation whose results can span more than one owner: --from-file, --range, --all-orgs, --my-orgs, --enterprise, --search, --all-linked, or --undo-log.")
+	templateCmd.Flags().BoolVar(&opts.validateOnly, "validate-only", false, "Parse and validate the --from-file manifest (schema, duplicate owner/number pairs) and report every issue, without making any network calls or mutations. Exits non-zero if validation fails. Requires --from-file.")
+	templateCmd.Flags().StringVar(&opts.repo, "repo", "", "Resolve the project owner from the repository `owner/name`, e.g. ${{ github.repository }} in an Actions workflow. Requires --project-number, or --all-linked to template every project linked to the repository instead.")
+	templateCmd.Flags().Int32Var(&opts.projectNumber, "project-number", 0, "Number of the project to template, for use with --repo.")
+	templateCmd.Flags().BoolVar(&opts.allLinked, "all-linked", false, "Template every project linked to the repository in --repo, instead of a single project by --project-number. Always requires confirmation or --yes regardless of --confirm-threshold.")
+	templateCmd.Flags().BoolVar(&opts.noAnnotations, "no-annotations", false, "Don't emit GitHub Actions \"::error::\"/\"::warning::\" workflow command annotations, even when GITHUB_ACTIONS=true is detected.")
+	templateCmd.Flags().BoolVar(&opts.quiet, "quiet", false, "Suppress every non-fatal message on stderr (warnings, rate-limit notices, match counts, the batch summary), regardless of whether stderr is a terminal. Combined with --format json, this produces pure machine output: only the JSON data on stdout, nothing else on any stream except a fatal error.")
+	templateCmd.Flags().StringVar(&opts.diffAgainst, "diff-against", "", "Compare the current template flags of the owner recorded in the `file` written by `template audit --dump-state` against that baseline, without marking or unmarking anything. Reports projects added, removed, or changed since the baseline, and exits non-zero if any drift is found unless --no-fail-on-drift is also set.")
+	templateCmd.Flags().BoolVar(&opts.noFailOnDrift, "no-fail-on-drift", false, "With --diff-against, still report drift but always exit zero, for monitoring that reads the JSON output itself instead of relying on the exit code.")
+	templateCmd.Flags().StringVar(&opts.templateName, "template-name", "", "Operate on the project number that --name-map maps this `name` to, instead of a project number argument, so scripts can refer to a stable name rather than a volatile number.")
+	templateCmd.Flags().StringVar(&opts.nameMap, "name-map", "", "YAML `file` mapping template names to project numbers, e.g. \"Standard Roadmap: 5\" (use \"-\" to read from standard input). Required by --template-name.")
+	templateCmd.Flags().StringVar(&opts.operationLog, "operation-log", "", "Append a JSON-lines record of each mark/unmark mutation (timestamp, owner, number, direction) to `path`, with enough information for --undo-log to later replay its inverse.")
+	templateCmd.Flags().StringVar(&opts.undoLog, "undo-log", "", "Replay the inverse of every operation recorded in the JSON-lines `file` written by --operation-log (mark becomes unmark and vice versa), skipping any already in the reverted state. Always requires confirmation or --yes; combine with --dry-run to preview first.")
+	cmdutil.AddFormatFlags(templateCmd, &opts.exporter)
+
+	templateCmd.AddCommand(newCmdAudit(f, nil))
+	templateCmd.AddCommand(newCmdDerivatives(f, nil))
+	templateCmd.AddCommand(newCmdWhich(f, nil))
+	templateCmd.AddCommand(newCmdStats(f, nil))
+	templateCmd.AddCommand(newCmdImport(f, nil))
+
+	return templateCmd
+}
+
+func runTemplate(config templateConfig) error {
+	if err := checkRateLimit(config); err != nil {
+		return err
+	}
+
+	if err := checkAllowWindow(config); err != nil {
+		return err
+	}
+
+	if config.opts.allLinked {
+		return runAllLinked(config)
+	}
+
+	if config.opts.repo != "" {
+		login, err := resolveRepoOwner(config)
+		if err != nil {
+			return fmt.Errorf("could not resolve owner from --repo: %w", err)
+		}
+		config.opts.owner = login
+	}
+
+	if config.opts.fromFile != "" {
+		return runManifest(config)
+	}
+
+	if config.opts.rangeExpr != "" {
+		return runRange(config)
+	}
+
+	if config.opts.search != "" {
+		return runSearch(config)
+	}
+
+	if config.opts.allOrgs {
+		return runAllOrgs(config)
+	}
+
+	if config.opts.myOrgs {
+		return runMyOrgs(config)
+	}
+
+	if config.opts.enterprise != "" {
+		return runEnterprise(config)
+	}
+
+	if config.opts.descriptionContains != "" {
+		return runDescriptionMatch(config)
+	}
+
+	if config.opts.fieldValue != "" {
+		return runFieldValue(config)
+	}
+
+	if config.opts.fieldOption != "" {
+		return runFieldOption(config)
+	}
+
+	if config.opts.restoreState != "" {
+		return runRestoreState(config)
+	}
+
+	if config.opts.diffAgainst != "" {
+		return runDiffAgainst(config)
+	}
+
+	if config.opts.undoLog != "" {
+		return runUndoLog(config)
+	}
+
+	if config.opts.id != "" {
+		return runByID(config)
+	}
+
+	start := time.Now()
+
+	owner, err := resolveOwner(config)
+	if err != nil {
+		return err
+	}
+
+	project, err := config.client.NewProject(config.io.CanPrompt(), owner, config.opts.number, false)
+	if err != nil {
+		return err
+	}
+	config.opts.projectID = project.ID
+
+	mutated, retries, noop, err := applyMutation(config, owner)
+	if err != nil {
+		return err
+	}
+
+	summary := batchSummary{Processed: 1}
+	switch {
+	case noop:
+		summary.Noop = 1
+	case config.opts.undo:
+		summary.Unmarked = 1
+	default:
+		summary.Marked = 1
+	}
+	if err := printBatchSummary(config, summary); err != nil {
+		return err
+	}
+	if err := writeMetricsFile(config.opts.metricsFile, summary, retries, time.Since(start)); err != nil {
+		return err
+	}
+
+	exported := mutated
+	if config.opts.undo {
+		exported = *project
+	}
+	if config.opts.exporter != nil {
+		if err := config.opts.exporter.Write(config.io, exported); err != nil {
+			return err
+		}
+	} else if err := printResults(config, mutated); err != nil {
+		return err
+	}
+	if err := writeOutputFile(config, exported); err != nil {
+		return err
+	}
+
+	if config.opts.linkRepo != "" {
+		if linkErr := linkProjectToRepo(config, mutated); linkErr != nil {
+			return fmt.Errorf("marked project %d as a template, but failed to link it to %s: %w", mutated.Number, config.opts.linkRepo, linkErr)
+		}
+		if err := printLinkResult(config, mutated); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMutation marks or unmarks the project identified by config.opts.projectID
+// as a template, depending on config.opts.undo, and returns the project node
+// from the mutation response, the number of verification retries performed
+// (always 0 unless --verify was passed), and whether the mutation was a
+// no-op (the project was already in the requested state). A GraphQL error
+// accompanied by a usable project node is treated as a warning rather than a
+// hard failure (see isPartialMutationError). By default the mutation
+// response's project node is trusted outright and no further read happens;
+// config.opts.verify opts into an extra follow-up read (see the caveat on
+// verifyTemplateMutation about what that read can and can't confirm) before
+// returning.
+func applyMutation(config templateConfig, owner *queries.Owner) (queries.Project, int, bool, error) {
+	hookEnv := hookEnvironment(config, owner)
+
+	if config.opts.preHook != "" {
+		if err := runHook(config, config.opts.preHook, hookEnv); err != nil {
+			return queries.Project{}, 0, false, fmt.Errorf("--pre-hook failed, aborting: %w", err)
+		}
+	}
+
+	project, retries, noop, err := doApplyMutation(config, owner)
+	if err != nil {
+		return project, retries, noop, err
+	}
+
+	if config.opts.operationLog != "" {
+		if err := appendOperationLog(config, owner.Login, config.opts.number, config.opts.undo); err != nil {
+			return project, retries, noop, fmt.Errorf("could not write --operation-log: %w", err)
+		}
+	}
+
+	if config.opts.postHook != "" {
+		hookEnv["GH_PROJECT_ID"] = project.ID
+		hookEnv["GH_PROJECT_NUMBER"] = strconv.Itoa(int(project.Number))
+		if err := runHook(config, config.opts.postHook, hookEnv); err != nil {
+			if warnErr := printPostHookWarning(config, err); warnErr != nil {
+				return project, retries, noop, warnErr
+			}
+		}
+	}
+
+	return project, retries, noop, nil
+}
+
+// doApplyMutation performs the mark/unmark mutation itself, without the
+// --pre-hook/--post-hook wrapping that applyMutation adds around it.
+func doApplyMutation(config templateConfig, owner *queries.Owner) (queries.Project, int, bool, error) {
+	if config.opts.undo {
+		query, variables := unmarkTemplateArgs(config)
+		err := config.client.Mutate("UnmarkProjectTemplate", query, variables)
+		noop := err != nil && isPartialMutationError(err, query.TemplateProject.Project)
+		if err != nil && !noop {
+			return queries.Project{}, 0, false, err
+		}
+		if err != nil {
+			if warnErr := printPartialMutationWarning(config, err); warnErr != nil {
+				return queries.Project{}, 0, false, warnErr
+			}
+		}
+
+		retries, err := verifyTemplateMutation(config, owner, query.TemplateProject.Project)
+		if err != nil {
+			return queries.Project{}, retries, false, err
+		}
+
+		return query.TemplateProject.Project, retries, noop, nil
+	}
+
+	query, variables := markTemplateArgs(config)
+	err := config.client.Mutate("MarkProjectTemplate", query, variables)
+	noop := err != nil && isPartialMutationError(err, query.TemplateProject.Project)
+	if err != nil && !noop {
+		return queries.Project{}, 0, false, err
+	}
+	if err != nil {
+		if warnErr := printPartialMutationWarning(config, err); warnErr != nil {
+			return queries.Project{}, 0, false, warnErr
+		}
+	}
+
+	retries, err := verifyTemplateMutation(config, owner, query.TemplateProject.Project)
+	if err != nil {
+		return queries.Project{}, retries, false, err
+	}
+
+	project := query.TemplateProject.Project
+	if config.opts.description != "" {
+		if updated, descErr := updateTemplateDescription(config); descErr != nil {
+			if warnErr := printDescriptionUpdateWarning(config, descErr); warnErr != nil {
+				return project, retries, noop, warnErr
+			}
+		} else {
+			project = updated
+		}
+	}
+
+	return project, retries, noop, nil
+}
+
+// batchSummary tallies the outcome of a template run. Every project a batch
+// mode considers falls into exactly one of Marked, Unmarked, Noop, Skipped,
+// or Failed, so Marked+Unmarked+Noop+Skipped+Failed always equals the number
+// of projects the batch considered.
+type batchSummary struct {
+	Processed int `json:"processed"`
+	Marked    int `json:"marked"`
+	Unmarked  int `json:"unmarked"`
+	Noop      int `json:"noop"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+}
+
+// printBatchSummary writes the tail summary for a template run to stderr,
+// in either human-readable text or JSON, leaving stdout free for the
+// primary --format/--output-null output.
+func printBatchSummary(config templateConfig, summary batchSummary) error {
+	if config.opts.quiet {
+		return nil
+	}
+
+	if config.opts.summaryFormat == "json" {
+		enc := json.NewEncoder(config.io.ErrOut)
+		return enc.Encode(summary)
+	}
+
+	if !config.io.IsStderrTTY() {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(config.io.ErrOut, "%d processed, %d marked, %d unmarked, %d noop, %d skipped, %d failed\n",
+		summary.Processed, summary.Marked, summary.Unmarked, summary.Noop, summary.Skipped, summary.Failed)
+	return err
+}
+
+// checkMaxAffected hard-stops a bulk operation before any mutation if count
+// exceeds opts.maxAffected. Unlike confirmBulkOperation and its siblings,
+// this cannot be satisfied with --yes or an interactive confirmation: it's a
+// safety net against a catastrophically mis-scoped selector (a typo'd
+// --range, an overly broad --search), not a prompt. A limit of 0 (the
+// default) disables the guard.
+func checkMaxAffected(config templateConfig, count int) error {
+	if config.opts.maxAffected == 0 || count <= config.opts.maxAffected {
+		return nil
+	}
+	return cmdutil.FlagErrorf("refusing to proceed: this operation would affect %d projects, which exceeds --max-affected %d", count, config.opts.maxAffected)
+}
+
+// confirmBulkOperation guards a bulk operation (--all-orgs,
+// --description-contains) that would affect count projects, prompting for
+// confirmation (or demanding --yes when the command cannot prompt) once
+// count exceeds opts.confirmThreshold. A threshold of 0 disables the guard
+// entirely, for scripts that have already made their own decision. subject
+// describes what would be affected, e.g. "3 matching projects". When the
+// operation is a bulk --undo and the guard is about to prompt interactively,
+// preview (if non-nil) is called to print a table of every project that
+// would be unmarked before asking for confirmation, so a typo'd filter
+// doesn't silently un-template a wide swath of projects.
+func confirmBulkOperation(config templateConfig, count int, subject string, preview func() ([]queries.Project, error)) error {
+	if err := checkMaxAffected(config, count); err != nil {
+		return err
+	}
+
+	if config.opts.confirmThreshold == 0 || count <= config.opts.confirmThreshold {
+		return nil
+	}
+
+	if config.opts.yes {
+		return nil
+	}
+
+	if !config.io.CanPrompt() {
+		return cmdutil.FlagErrorf("--yes is required when a bulk operation would affect more than --confirm-threshold %d projects (got %d)", config.opts.confirmThreshold, count)
+	}
+
+	if config.opts.undo && preview != nil {
+		projects, err := preview()
+		if err != nil {
+			return err
+		}
+		if err := printUndoPreview(config, projects); err != nil {
+			return err
+		}
+	}
+
+	confirmed, err := config.prompter.Confirm(fmt.Sprintf("This will %s %s. Continue?", verbForUndo(config.opts.undo), subject), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cmdutil.CancelError
+	}
+	return nil
+}
+
+// printUndoPreview renders a table of number + title for every project a
+// bulk --undo is about to unmark, ahead of the confirmation prompt.
+func printUndoPreview(config templateConfig, projects []queries.Project) error {
+	tp := tableprinter.New(config.io, tableprinter.WithHeader("Number", "Title"))
+	for _, p := range projects {
+		tp.AddField(strconv.Itoa(int(p.Number)), tableprinter.WithTruncate(nil))
+		tp.AddField(p.Title)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+// printEmptyProjectList reports a clean, successful empty result for a
+// listing/batch path that matched no projects, rather than letting the
+// caller fall through to a generic zero-count summary line. Owners with no
+// projects yet (or no projects matching a filter) are a normal outcome, not
+// an error.
+func printEmptyProjectList(config templateConfig) error {
+	if config.opts.exporter != nil {
+		return config.opts.exporter.Write(config.io, []matchResult{})
+	}
+
+	if config.io.IsStderrTTY() && !config.opts.quiet {
+		_, err := fmt.Fprintln(config.io.ErrOut, "No projects found.")
+		return err
+	}
+
+	return nil
+}
+
+// resolveOwner determines the project owner to use, falling back to the
+// authenticated identity when no --owner is given and the command cannot
+// prompt interactively. This covers GitHub App-authenticated runs, where
+// the installation account is implicit and there is no owner to prompt for.
+func resolveOwner(config templateConfig) (*queries.Owner, error) {
+	canPrompt := config.io.CanPrompt()
+	if config.opts.owner != "" || canPrompt {
+		return config.client.NewOwner(canPrompt, config.opts.owner)
+	}
+
+	login, err := resolveViewerLogin(config)
+	if err != nil {
+		return nil, fmt.Errorf("owner is required when not running interactively")
+	}
+	return config.client.NewOwner(canPrompt, login)
+}
+
+// isPartialMutationError reports whether err represents a GraphQL response
+// that returned both errors and a usable projectV2 node, meaning the
+// mutation's core effect likely succeeded despite the accompanying errors.
+// This disambiguates "failed entirely" from "succeeded with warnings".
+func isPartialMutationError(err error, project queries.Project) bool {
+	var gerr api.GraphQLError
+	if !errors.As(err, &gerr) {
+		return false
+	}
+	return project.ID != ""
+}
+
+// verifyTemplateMutation confirms that mutated is still readable by owner
+// and number on a follow-up read, retrying up to opts.verifyRetries times to
+// ride out eventual-consistency lag between the mutation and subsequent
+// reads, and returns how many retries were needed.
+//
+// It compares project IDs rather than the Template field itself, because
+// queries.Project doesn't carry that field at all (see the comment on
+// queries.Project, which was removed for GHES 3.8 compatibility). That means
+// --verify cannot actually confirm the mark/unmark took effect: a project's
+// ID doesn't change when its template flag does, so the ID comparison
+// succeeds on the very first read in ordinary operation and never drives a
+// retry. What it does confirm is that the project is still there, under the
+// same ID, immediately after the mutation — which catches a read erroring
+// or timing out right after a write (e.g. routing to a stale replica), and,
+// via the concurrent-modification check below, a project number that now
+// resolves to a different project entirely. Treat --verify as "the project
+// didn't disappear or change identity out from under us," not as proof the
+// template flag is now set. It is a no-op unless --verify was passed.
+// --timeout bounds the retries as a whole; --timeout-per-retry
+// additionally bounds each individual attempt, so one slow read can be
+// canceled and retried within whatever of --timeout's budget remains,
+// rather than consuming it all on a single hung request. The delay between
+// retries grows exponentially from --verify-interval, capped at
+// --max-backoff. If every retry is exhausted without a transport error but
+// the final read-back still shows a different, non-empty project ID than
+// the one just mutated, that's not eventual-consistency lag catching up --
+// it's evidence a concurrent writer changed the project in between, so the
+// returned error says so explicitly rather than reporting a generic
+// verification failure.
+func verifyTemplateMutation(config templateConfig, owner *queries.Owner, mutated queries.Project) (int, error) {
+	if !config.opts.verify {
+		return 0, nil
+	}
+
+	ctx := context.Background()
+	if config.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.opts.timeout)
+		defer cancel()
+	}
+
+	var lastErr error
+	var lastFresh queries.Project
+	attempt := 1
+	for ; attempt <= config.opts.verifyRetries+1; attempt++ {
+		attemptCtx := ctx
+		cancelAttempt := func() {}
+		if config.opts.timeoutPerRetry > 0 {
+			attemptCtx, cancelAttempt = context.WithTimeout(ctx, config.opts.timeoutPerRetry)
+		}
+		fresh, err := config.client.NewProjectWithContext(attemptCtx, owner, config.opts.number)
+		cancelAttempt()
+
+		if err == nil && mutated.ID != "" && fresh.ID == mutated.ID {
+			if config.opts.verbose && !config.opts.quiet {
+				if attempt == 1 {
+					fmt.Fprintln(config.io.ErrOut, "verified immediately")
+				} else {
+					fmt.Fprintf(config.io.ErrOut, "verified after %d retries\n", attempt-1)
+				}
+			}
+			return attempt - 1, nil
+		}
+		lastErr = err
+		if err == nil && fresh != nil {
+			lastFresh = *fresh
+		}
+
+		if ctx.Err() != nil {
+			// --timeout's overall deadline is gone; further retries would
+			// just fail the same way, so stop early instead of sleeping
+			// through backoffs against a budget that's already spent.
+			break
+		}
+
+		if attempt <= config.opts.verifyRetries {
+			backoff := backoffForAttempt(config.opts.verifyInterval, config.opts.maxBackoff, attempt, config.opts.rng)
+			if config.opts.retryLog != "" {
+				if logErr := appendRetryLog(config, attempt, err, backoff); logErr != nil {
+					return attempt - 1, fmt.Errorf("could not write --retry-log: %w", logErr)
+				}
+			}
+			config.opts.sleep(backoff)
+		}
+	}
+
+	attemptsMade := attempt
+	if attemptsMade > config.opts.verifyRetries+1 {
+		attemptsMade = config.opts.verifyRetries + 1
+	}
+	retries := attemptsMade - 1
+	if lastErr != nil {
+		return retries, fmt.Errorf("could not verify template change: %w", lastErr)
+	}
+	if lastFresh.ID != "" && lastFresh.ID != mutated.ID {
+		// The last read-back succeeded and returned a real, different
+		// project state rather than erroring or coming back empty, which
+		// rules out replication lag or a not-found response: something else
+		// changed this project's state in between our mutation and our
+		// verification reads.
+		return retries, fmt.Errorf("concurrent modification detected: expected project %q but read back %q after %d attempts; another process may have changed this project concurrently", mutated.ID, lastFresh.ID, attemptsMade)
+	}
+	return retries, fmt.Errorf("could not verify template change after %d attempts", attemptsMade)
+}
+
+// retryLogEntry is one JSON line written to --retry-log: a single
+// verification retry attempt, meant to be correlated offline against
+// GitHub's incident timeline to spot patterns in flakiness.
+type retryLogEntry struct {
+	Timestamp string `json:"timestamp"`
+	Attempt   int    `json:"attempt"`
+	Error     string `json:"error"`
+	BackoffMS int64  `json:"backoff_ms"`
+}
+
+// appendRetryLog appends a retryLogEntry for attempt to config.opts.retryLog.
+// attemptErr is redacted with redactTokens before being written, since a
+// transport error can embed request details that carry the user's token.
+func appendRetryLog(config templateConfig, attempt int, attemptErr error, backoff time.Duration) error {
+	f, err := os.OpenFile(config.opts.retryLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	message := ""
+	if attemptErr != nil {
+		message = redactTokens(attemptErr.Error())
+	}
+
+	return json.NewEncoder(f).Encode(retryLogEntry{
+		Timestamp: config.opts.now().UTC().Format(time.RFC3339),
+		Attempt:   attempt,
+		Error:     message,
+		BackoffMS: backoff.Milliseconds(),
+	})
+}
+
+// validateRetryLogPath confirms path can be opened for appending, so
+// --retry-log fails fast on a bad path rather than partway through a long
+// --verify retry loop.
+func validateRetryLogPath(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// tokenPattern matches GitHub's token prefixes (personal access, OAuth, and
+// installation tokens), so a credential that leaks into a transport error
+// message isn't written to the retry log verbatim.
+var tokenPattern = regexp.MustCompile(`\bgh[poseiu]_[A-Za-z0-9]{20,}\b`)
+
+// redactTokens replaces any GitHub token in s with a placeholder.
+func redactTokens(s string) string {
+	return tokenPattern.ReplaceAllString(s, "REDACTED")
+}
+
+func printPartialMutationWarning(config templateConfig, err error) error {
+	if config.opts.quietErrors || config.opts.quiet {
+		return nil
+	}
+	if werr := printWarningAnnotation(config, err.Error()); werr != nil {
+		return werr
+	}
+	cs := config.io.ColorScheme()
+	_, werr := fmt.Fprintf(config.io.ErrOut, "%s %s\n", cs.WarningIcon(), err)
+	return werr
+}
+
+// printPostHookWarning reports a --post-hook failure as a warning rather
+// than a hard failure, since the mutation it ran after already succeeded;
+// treating it as fatal would make a notification or audit side effect as
+// important as the template change itself.
+func printPostHookWarning(config templateConfig, err error) error {
+	if config.opts.quietErrors || config.opts.quiet {
+		return nil
+	}
+	if werr := printWarningAnnotation(config, fmt.Sprintf("--post-hook failed: %s", err)); werr != nil {
+		return werr
+	}
+	cs := config.io.ColorScheme()
+	_, werr := fmt.Fprintf(config.io.ErrOut, "%s --post-hook failed: %s\n", cs.WarningIcon(), err)
+	return werr
+}
+
+func markTemplateArgs(config templateConfig) (*markProjectTemplateMutation, map[string]interface{}) {
+	return &markProjectTemplateMutation{}, map[string]interface{}{
+		"input": githubv4.MarkProjectV2AsTemplateInput{
+			ProjectID: githubv4.ID(config.opts.projectID),
+		},
+		"firstItems":  githubv4.Int(0),
+		"afterItems":  (*githubv4.String)(nil),
+		"firstFields": githubv4.Int(0),
+		"afterFields": (*githubv4.String)(nil),
+	}
+}
+
+func unmarkTemplateArgs(config templateConfig) (*unmarkProjectTemplateMutation, map[string]interface{}) {
+	return &unmarkProjectTemplateMutation{}, map[string]interface{}{
+		"input": githubv4.UnmarkProjectV2AsTemplateInput{
+			ProjectID: githubv4.ID(config.opts.projectID),
+		},
+		"firstItems":  githubv4.Int(0),
+		"afterItems":  (*githubv4.String)(nil),
+		"firstFields": githubv4.Int(0),
+		"afterFields": (*githubv4.String)(nil),
+	}
+}
+
+func updateDescriptionArgs(config templateConfig) (*updateTemplateDescriptionMutation, map[string]interface{}) {
+	return &updateTemplateDescriptionMutation{}, map[string]interface{}{
+		"input": githubv4.UpdateProjectV2Input{
+			ProjectID:        githubv4.ID(config.opts.projectID),
+			ShortDescription: githubv4.NewString(githubv4.String(config.opts.description)),
+		},
+		"firstItems":  githubv4.Int(0),
+		"afterItems":  (*githubv4.String)(nil),
+		"firstFields": githubv4.Int(0),
+		"afterFields": (*githubv4.String)(nil),
+	}
+}
+
+// updateTemplateDescription sets the project's shortDescription alongside
+// marking it as a template, composing the two setup steps into one call. It
+// is called only after the mark mutation has already succeeded (or noop'd),
+// so a failure here is reported as a warning rather than undoing the mark.
+func updateTemplateDescription(config templateConfig) (queries.Project, error) {
+	query, variables := updateDescriptionArgs(config)
+	if err := config.client.Mutate("UpdateProjectV2", query, variables); err != nil {
+		return queries.Project{}, err
+	}
+	return query.UpdateProjectV2.Project, nil
+}
+
+// printDescriptionUpdateWarning reports a --description update failure as a
+// warning rather than a hard failure, since the mark mutation it follows
+// already succeeded; failing the whole command would make the description a
+// harder requirement than the template mark itself.
+func printDescriptionUpdateWarning(config templateConfig, err error) error {
+	if config.opts.quietErrors || config.opts.quiet {
+		return nil
+	}
+	if werr := printWarningAnnotation(config, fmt.Sprintf("could not set --description: %s", err)); werr != nil {
+		return werr
+	}
+	cs := config.io.ColorScheme()
+	_, werr := fmt.Fprintf(config.io.ErrOut, "%s could not set --description: %s\n", cs.WarningIcon(), err)
+	return werr
+}
+
+func printResults(config templateConfig, project queries.Project) error {
+	if config.opts.shellExport {
+		return printShellExport(config, project)
+	}
+
+	if config.opts.outputNull {
+		_, err := fmt.Fprintf(config.io.Out, "%s\x00", project.URL)
+		return err
+	}
+
+	if !config.io.IsStdoutTTY() {
+		return nil
+	}
+
+	if config.opts.undo {
+		_, err := fmt.Fprintf(config.io.Out, "Unmarked project %d as a template.\n", project.Number)
+		return err
+	}
+
+	_, err := fmt.Fprintf(config.io.Out, "Marked project %d as a template.\n", project.Number)
+	return err
+}
+
+// printShellExport writes project's ID and number to stdout as shell-quoted
+// assignments, so a caller can pick them up with
+// `eval "$(gh project template ... --shell-export)"` in a later step of a
+// shell pipeline.
+func printShellExport(config templateConfig, project queries.Project) error {
+	_, err := fmt.Fprintf(config.io.Out, "GH_PROJECT_ID=%s\nGH_PROJECT_NUMBER=%s\n",
+		shellquote.Join(project.ID), shellquote.Join(strconv.Itoa(int(project.Number))))
+	return err
+}
@@ -0,0 +1,52 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"gopkg.in/yaml.v3"
+)
+
+// loadNameMap reads and parses the YAML name-to-number mapping file at path
+// (use "-" to read from standard input), for resolving --template-name
+// against --name-map. The mapping is entirely client-side: nothing here
+// talks to the API.
+func loadNameMap(path string, stdin io.ReadCloser) (map[string]int32, error) {
+	data, err := cmdutil.ReadFile(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	var names map[string]int32
+	if err := yaml.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("could not parse --name-map: %w", err)
+	}
+
+	return names, nil
+}
+
+// resolveTemplateName looks up name in the --name-map file at path, so
+// scripts can refer to a project by a stable human-assigned name instead of
+// its volatile number. An unknown name errors with the full set of names
+// the mapping file does define, sorted for a deterministic message.
+func resolveTemplateName(name, path string, stdin io.ReadCloser) (int32, error) {
+	names, err := loadNameMap(path, stdin)
+	if err != nil {
+		return 0, err
+	}
+
+	if number, ok := names[name]; ok {
+		return number, nil
+	}
+
+	available := make([]string, 0, len(names))
+	for n := range names {
+		available = append(available, n)
+	}
+	sort.Strings(available)
+
+	return 0, fmt.Errorf("%q is not a known template name; available names are: %s", name, strings.Join(available, ", "))
+}
@@ -0,0 +1,128 @@
+package template
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/google/shlex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTemplateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		lookup  string
+		want    int32
+		wantErr string
+	}{
+		{
+			name:    "known name",
+			content: "Standard Roadmap: 5\nBug Triage: 12\n",
+			lookup:  "Standard Roadmap",
+			want:    5,
+		},
+		{
+			name:    "unknown name lists available names",
+			content: "Standard Roadmap: 5\nBug Triage: 12\n",
+			lookup:  "Sprint Planning",
+			wantErr: `"Sprint Planning" is not a known template name; available names are: Bug Triage, Standard Roadmap`,
+		},
+		{
+			name:    "invalid yaml",
+			content: "not: [valid",
+			lookup:  "anything",
+			wantErr: "could not parse --name-map",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := dir + "/names.yml"
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			got, err := resolveTemplateName(tt.lookup, path, io.NopCloser(nil))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewCmdTemplate_TemplateName(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/names.yml"
+	assert.NoError(t, os.WriteFile(path, []byte("Standard Roadmap: 5\n"), 0600))
+
+	tests := []struct {
+		name        string
+		cli         string
+		wantNumber  int32
+		wantErr     bool
+		wantsErrMsg string
+	}{
+		{
+			name:       "resolves name to number",
+			cli:        `--owner github --template-name "Standard Roadmap" --name-map ` + path,
+			wantNumber: 5,
+		},
+		{
+			name:        "template-name requires name-map",
+			cli:         `--owner github --template-name "Standard Roadmap"`,
+			wantErr:     true,
+			wantsErrMsg: "--template-name requires --name-map",
+		},
+		{
+			name:        "name-map requires template-name",
+			cli:         `--owner github --name-map ` + path,
+			wantErr:     true,
+			wantsErrMsg: "--name-map requires --template-name",
+		},
+		{
+			name:        "cannot combine with a project number",
+			cli:         `5 --owner github --template-name "Standard Roadmap" --name-map ` + path,
+			wantErr:     true,
+			wantsErrMsg: "--template-name cannot be combined with a project number",
+		},
+		{
+			name:        "unknown name",
+			cli:         `--owner github --template-name "Sprint Planning" --name-map ` + path,
+			wantErr:     true,
+			wantsErrMsg: `"Sprint Planning" is not a known template name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argv, err := shlex.Split(tt.cli)
+			assert.NoError(t, err)
+
+			ios, _, _, _ := iostreams.Test()
+			f := &cmdutil.Factory{
+				IOStreams: ios,
+			}
+
+			var gotOpts templateOpts
+			cmd := NewCmdTemplate(f, func(config templateConfig) error {
+				gotOpts = config.opts
+				return nil
+			})
+			cmd.SetArgs(argv)
+			_, err = cmd.ExecuteC()
+
+			if tt.wantErr {
+				assert.ErrorContains(t, err, tt.wantsErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNumber, gotOpts.number)
+		})
+	}
+}
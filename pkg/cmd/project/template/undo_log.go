@@ -0,0 +1,122 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cli/cli/v2/pkg/cmdutil"
+)
+
+// loadOperationLog reads and parses the JSON-lines document written by
+// --operation-log at path (use "-" to read from stdin), returning its
+// entries in recorded order.
+func loadOperationLog(path string, config templateConfig) ([]operationLogEntry, error) {
+	data, err := cmdutil.ReadFile(path, config.io.In)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []operationLogEntry
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var entry operationLogEntry
+		if err := dec.Decode(&entry); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("could not parse --undo-log: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// invertOperationLog turns logEntries into the manifestEntry batch that
+// undoes them: each entry's recorded direction is flipped (mark becomes
+// unmark and vice versa), so the result can be handed straight to
+// runEntries, the same batch engine --from-file and --range use. An entry
+// whose inverse has already been applied (e.g. the project was unmarked by
+// some other means since being logged) comes back as a noop from
+// applyMutation just like it would for any other batch entry, rather than
+// needing its own "already reverted" tracking here.
+func invertOperationLog(logEntries []operationLogEntry) []manifestEntry {
+	entries := make([]manifestEntry, len(logEntries))
+	for i, e := range logEntries {
+		entries[i] = manifestEntry{Owner: e.Owner, Number: e.Number, Undo: !e.Undo}
+	}
+	return entries
+}
+
+// printUndoLogPreview lists the changes a --undo-log run would make,
+// without making them, for --dry-run.
+func printUndoLogPreview(config templateConfig, entries []manifestEntry) error {
+	for _, e := range entries {
+		verb := "mark"
+		if e.Undo {
+			verb = "unmark"
+		}
+		if _, err := fmt.Fprintf(config.io.Out, "would %s project %d (%s) as a template\n", verb, e.Number, e.Owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmUndoLog requires --yes, or an interactive confirmation, before
+// replaying count recorded operations, since a stale operation log can
+// affect projects that have since changed hands or state.
+func confirmUndoLog(config templateConfig, count int) error {
+	if err := checkMaxAffected(config, count); err != nil {
+		return err
+	}
+
+	if config.opts.yes {
+		return nil
+	}
+	if !config.io.CanPrompt() {
+		return cmdutil.FlagErrorf("--yes is required to replay %d operations from --undo-log", count)
+	}
+	confirmed, err := config.prompter.Confirm(fmt.Sprintf("This will revert %d recorded operations from %s. Continue?", count, config.opts.undoLog), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cmdutil.CancelError
+	}
+	return nil
+}
+
+// runUndoLog replays the inverse of every operation recorded in
+// config.opts.undoLog, giving batch sessions recorded with
+// --operation-log a true undo. Missing projects and already-reverted
+// operations surface the same way any other runEntries batch handles a
+// failing or no-op entry: the run continues and the outcome is tallied in
+// the batch summary rather than aborting the whole replay.
+func runUndoLog(config templateConfig) error {
+	logEntries, err := loadOperationLog(config.opts.undoLog, config)
+	if err != nil {
+		return err
+	}
+
+	if len(logEntries) == 0 {
+		if config.io.IsStderrTTY() && !config.opts.quiet {
+			fmt.Fprintln(config.io.ErrOut, "Operation log is empty; nothing to undo.")
+		}
+		return nil
+	}
+
+	entries := invertOperationLog(logEntries)
+
+	if config.opts.dryRun {
+		return printUndoLogPreview(config, entries)
+	}
+
+	if err := confirmUndoLog(config, len(entries)); err != nil {
+		return err
+	}
+
+	return runEntries(config, entries)
+}
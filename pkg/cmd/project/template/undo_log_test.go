@@ -0,0 +1,155 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/internal/prompter"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestInvertOperationLog(t *testing.T) {
+	entries := invertOperationLog([]operationLogEntry{
+		{Owner: "github", Number: 1, Undo: false},
+		{Owner: "github", Number: 2, Undo: true},
+	})
+
+	assert.Equal(t, []manifestEntry{
+		{Owner: "github", Number: 1, Undo: true},
+		{Owner: "github", Number: 2, Undo: false},
+	}, entries)
+}
+
+func TestLoadOperationLog(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/ops.jsonl"
+	writeTestFile(t, path, "{\"timestamp\":\"2024-01-01T00:00:00Z\",\"owner\":\"github\",\"number\":1,\"undo\":false}\n{\"timestamp\":\"2024-01-01T00:01:00Z\",\"owner\":\"github\",\"number\":2,\"undo\":true}\n")
+
+	ios, _, _, _ := iostreams.Test()
+	entries, err := loadOperationLog(path, templateConfig{io: ios})
+	assert.NoError(t, err)
+	assert.Equal(t, []operationLogEntry{
+		{Timestamp: "2024-01-01T00:00:00Z", Owner: "github", Number: 1, Undo: false},
+		{Timestamp: "2024-01-01T00:01:00Z", Owner: "github", Number: 2, Undo: true},
+	}, entries)
+}
+
+func TestRunUndoLog_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/ops.jsonl"
+	writeTestFile(t, path, "{\"owner\":\"github\",\"number\":1,\"undo\":false}\n{\"owner\":\"github\",\"number\":2,\"undo\":true}\n")
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := templateConfig{
+		opts: templateOpts{undoLog: path, dryRun: true},
+		io:   ios,
+	}
+
+	err := runUndoLog(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "would unmark project 1 (github) as a template\nwould mark project 2 (github) as a template\n", stdout.String())
+}
+
+func TestRunUndoLog_RequiresYesNonInteractive(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/ops.jsonl"
+	writeTestFile(t, path, "{\"owner\":\"github\",\"number\":1,\"undo\":false}\n")
+
+	ios, _, _, _ := iostreams.Test()
+	config := templateConfig{
+		opts: templateOpts{undoLog: path},
+		io:   ios,
+	}
+
+	err := runUndoLog(config)
+	assert.EqualError(t, err, "--yes is required to replay 1 operations from --undo-log")
+}
+
+func TestRunUndoLog_Empty(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/ops.jsonl"
+	writeTestFile(t, path, "")
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts: templateOpts{undoLog: path},
+		io:   ios,
+	}
+
+	err := runUndoLog(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "Operation log is empty; nothing to undo.\n", stderr.String())
+}
+
+func TestRunUndoLog(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	dir := t.TempDir()
+	path := dir + "/ops.jsonl"
+	writeTestFile(t, path, "{\"owner\":\"github\",\"number\":1,\"undo\":false}\n")
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*query UserOrgOwner.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{"login": "github", "id": "org ID"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{"type": "NOT_FOUND", "path": []string{"user"}},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"query": "query OrgProject.*",
+			"variables": map[string]interface{}{
+				"login":       "github",
+				"number":      1,
+				"firstItems":  0,
+				"afterItems":  nil,
+				"firstFields": 0,
+				"afterFields": nil,
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID", "number": 1},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"mutation UnmarkProjectTemplate.*","variables":{"afterFields":null,"afterItems":null,"firstFields":0,"firstItems":0,"input":{"projectId":"project ID"}}}`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"unmarkProjectV2AsTemplate": map[string]interface{}{
+					"projectV2": map[string]interface{}{"id": "project ID", "number": 1},
+				},
+			},
+		})
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStderrTTY(true)
+	config := templateConfig{
+		opts:     templateOpts{undoLog: path, yes: true, concurrency: 1, ownerConcurrency: 1, summaryFormat: "text"},
+		client:   queries.NewTestClient(),
+		io:       ios,
+		prompter: &prompter.PrompterMock{},
+	}
+
+	err := runUndoLog(config)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "1 processed, 0 marked, 1 unmarked, 0 noop, 0 skipped, 0 failed")
+}
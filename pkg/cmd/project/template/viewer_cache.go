@@ -0,0 +1,113 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cli/cli/v2/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultViewerCacheTTL is how long a disk-cached viewer login stays valid.
+// It mirrors the update-check state file's day-scale TTL in spirit, but
+// shorter, since a viewer's login rarely changes but their org memberships
+// (which affect owner resolution elsewhere) can.
+const defaultViewerCacheTTL = time.Hour
+
+// viewerLoginMemo holds the in-process memoization of the viewer's login for
+// "--viewer-cache memory" (the default). It is shared by pointer across the
+// opts copies each --from-file manifest entry makes, so every entry in a
+// batch after the first reuses the same lookup.
+type viewerLoginMemo struct {
+	login  string
+	filled bool
+}
+
+type viewerCacheEntry struct {
+	Login    string    `yaml:"login"`
+	CachedAt time.Time `yaml:"cachedAt"`
+}
+
+// viewerCacheFilePath returns the on-disk location used by "--viewer-cache
+// disk", under the same state directory gh uses for its own update-check
+// cache. dir overrides config.StateDir() when set, which tests use to avoid
+// touching the real gh state directory.
+func viewerCacheFilePath(dir string) string {
+	if dir == "" {
+		dir = config.StateDir()
+	}
+	return filepath.Join(dir, "project-template-viewer-cache.yml")
+}
+
+func readViewerCacheDisk(path string, ttl time.Duration) (string, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	var entry viewerCacheEntry
+	if err := yaml.Unmarshal(content, &entry); err != nil {
+		return "", false
+	}
+
+	if time.Since(entry.CachedAt) >= ttl {
+		return "", false
+	}
+
+	return entry.Login, true
+}
+
+func writeViewerCacheDisk(path, login string) error {
+	content, err := yaml.Marshal(viewerCacheEntry{Login: login, CachedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0600)
+}
+
+// resolveViewerLogin returns the authenticated user's login, applying
+// config.opts.viewerCache:
+//   - "none" always queries.
+//   - "memory" (the default) queries at most once per command invocation.
+//   - "disk" additionally persists the result across invocations at
+//     viewerCacheFilePath, valid for defaultViewerCacheTTL.
+func resolveViewerLogin(config templateConfig) (string, error) {
+	switch config.opts.viewerCache {
+	case "disk":
+		path := viewerCacheFilePath(config.opts.viewerCacheDir)
+		if login, ok := readViewerCacheDisk(path, defaultViewerCacheTTL); ok {
+			return login, nil
+		}
+
+		login, err := config.client.ViewerLoginName()
+		if err != nil {
+			return "", err
+		}
+		_ = writeViewerCacheDisk(path, login)
+		return login, nil
+
+	case "none":
+		return config.client.ViewerLoginName()
+
+	default: // "memory"
+		if config.opts.viewerMemo != nil && config.opts.viewerMemo.filled {
+			return config.opts.viewerMemo.login, nil
+		}
+
+		login, err := config.client.ViewerLoginName()
+		if err != nil {
+			return "", err
+		}
+		if config.opts.viewerMemo != nil {
+			config.opts.viewerMemo.login = login
+			config.opts.viewerMemo.filled = true
+		}
+		return login, nil
+	}
+}
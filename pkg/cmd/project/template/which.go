@@ -0,0 +1,95 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/client"
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type whichOpts struct {
+	owner    string
+	number   int32
+	exporter cmdutil.Exporter
+}
+
+type whichConfig struct {
+	client *queries.Client
+	opts   whichOpts
+	io     *iostreams.IOStreams
+}
+
+// newCmdWhich returns the read-only `gh project template which` subcommand,
+// the reverse of derivatives: given a project, it finds the template it was
+// created from via "use as template", so a team can trace a project's
+// lineage back to its source.
+func newCmdWhich(f *cmdutil.Factory, runF func(config whichConfig) error) *cobra.Command {
+	opts := whichOpts{}
+	whichCmd := &cobra.Command{
+		Use:   "which <number>",
+		Short: "Find the template a project was created from",
+		Example: heredoc.Doc(`
+			# find which template project 5 in the github org was derived from
+			gh project template which 5 --owner github
+
+			# as JSON, for scripting
+			gh project template which 5 --owner github --format json
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.owner == "" {
+				return cmdutil.FlagErrorf("--owner is required")
+			}
+
+			number, err := parseProjectNumber(args[0])
+			if err != nil {
+				return cmdutil.FlagErrorf("%s", err)
+			}
+			opts.number = number
+
+			client, err := client.New(f)
+			if err != nil {
+				return err
+			}
+
+			config := whichConfig{
+				client: client,
+				opts:   opts,
+				io:     f.IOStreams,
+			}
+
+			if runF != nil {
+				return runF(config)
+			}
+			return runWhich(config)
+		},
+	}
+
+	whichCmd.Flags().StringVar(&opts.owner, "owner", "", "Login of the project's organization.")
+	cmdutil.AddFormatFlags(whichCmd, &opts.exporter)
+
+	return whichCmd
+}
+
+func runWhich(config whichConfig) error {
+	source, err := config.client.SourceTemplate(config.opts.owner, config.opts.number)
+	if err != nil {
+		return fmt.Errorf("could not look up source template: %w", err)
+	}
+
+	if config.opts.exporter != nil {
+		return config.opts.exporter.Write(config.io, source)
+	}
+
+	if source == nil {
+		_, err := fmt.Fprintln(config.io.Out, "this project was not created from a template")
+		return err
+	}
+
+	_, err = fmt.Fprintf(config.io.Out, "#%d %s (%s)\n", source.Number, source.Title, source.OwnerLogin())
+	return err
+}
@@ -0,0 +1,137 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/project/shared/queries"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRunWhich(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"name": "sourceTemplate"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectSourceTemplate.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"sourceTemplate": map[string]interface{}{
+							"number": 1,
+							"title":  "Team Roadmap Template",
+							"url":    "https://github.com/orgs/github/projects/1",
+							"owner": map[string]interface{}{
+								"__typename": "Organization",
+								"login":      "github",
+							},
+						},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := whichConfig{
+		opts:   whichOpts{owner: "github", number: 7},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runWhich(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "#1 Team Roadmap Template (github)\n", stdout.String())
+}
+
+func TestRunWhich_NotDerived(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"name": "sourceTemplate"},
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectSourceTemplate.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"organization": map[string]interface{}{
+					"projectV2": map[string]interface{}{
+						"sourceTemplate": map[string]interface{}{},
+					},
+				},
+			},
+		})
+
+	ios, _, stdout, _ := iostreams.Test()
+	config := whichConfig{
+		opts:   whichOpts{owner: "github", number: 7},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runWhich(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "this project was not created from a template\n", stdout.String())
+}
+
+func TestRunWhich_Unsupported(t *testing.T) {
+	defer gock.Off()
+	gock.Observe(gock.DumpRequest)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`.*ProjectTemplate_capabilities.*`).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"data": map[string]interface{}{
+				"Mutation":     map[string]interface{}{"fields": []interface{}{}},
+				"Organization": map[string]interface{}{"fields": []interface{}{}},
+				"ProjectV2":    map[string]interface{}{"fields": []interface{}{}},
+			},
+		})
+
+	ios, _, _, _ := iostreams.Test()
+	config := whichConfig{
+		opts:   whichOpts{owner: "github", number: 7},
+		client: queries.NewTestClient(),
+		io:     ios,
+	}
+
+	err := runWhich(config)
+	assert.EqualError(t, err, `could not look up source template: this host's GraphQL schema does not expose a project's source template`)
+}
@@ -0,0 +1,164 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// allowWindow is a parsed --allow-window spec: a day-of-week range and a
+// time-of-day range, both inclusive, evaluated in a fixed IANA zoneinfo
+// location rather than the host's local time, so a governance job scheduled
+// against "America/New_York" behaves the same no matter where it runs.
+type allowWindow struct {
+	startDay, endDay time.Weekday
+	startMin, endMin int // minutes since midnight
+	loc              *time.Location
+}
+
+var weekdaysByAbbrev = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// parseAllowWindow parses a --allow-window spec of the form
+// "Mon-Fri 09:00-17:00 America/New_York": a three-letter weekday range, a
+// 24-hour "HH:MM-HH:MM" time range, and an IANA zoneinfo location name. Both
+// ranges may wrap around (e.g. "Fri-Mon" or "22:00-06:00") to describe a
+// window that spans the end of the week or the end of the day.
+func parseAllowWindow(spec string) (*allowWindow, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid --allow-window %q: expected \"<start day>-<end day> <start time>-<end time> <zoneinfo location>\"", spec)
+	}
+	dayRange, timeRange, locName := fields[0], fields[1], fields[2]
+
+	startDayStr, endDayStr, ok := strings.Cut(dayRange, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid --allow-window day range %q: expected \"<start day>-<end day>\", e.g. \"Mon-Fri\"", dayRange)
+	}
+	startDay, ok := weekdaysByAbbrev[startDayStr]
+	if !ok {
+		return nil, fmt.Errorf("invalid --allow-window day %q: expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", startDayStr)
+	}
+	endDay, ok := weekdaysByAbbrev[endDayStr]
+	if !ok {
+		return nil, fmt.Errorf("invalid --allow-window day %q: expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", endDayStr)
+	}
+
+	startTimeStr, endTimeStr, ok := strings.Cut(timeRange, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid --allow-window time range %q: expected \"<start time>-<end time>\", e.g. \"09:00-17:00\"", timeRange)
+	}
+	startMin, err := parseClockMinutes(startTimeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --allow-window start time %q: %s", startTimeStr, err)
+	}
+	endMin, err := parseClockMinutes(endTimeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --allow-window end time %q: %s", endTimeStr, err)
+	}
+
+	loc, err := time.LoadLocation(locName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --allow-window location %q: %s", locName, err)
+	}
+
+	return &allowWindow{
+		startDay: startDay,
+		endDay:   endDay,
+		startMin: startMin,
+		endMin:   endMin,
+		loc:      loc,
+	}, nil
+}
+
+// parseClockMinutes parses a "15:04"-formatted time of day into minutes
+// since midnight.
+func parseClockMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("expected 24-hour \"HH:MM\"")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether now falls inside the window, once converted to
+// the window's location.
+func (w *allowWindow) contains(now time.Time) bool {
+	local := now.In(w.loc)
+
+	if !weekdayInRange(local.Weekday(), w.startDay, w.endDay) {
+		return false
+	}
+
+	minutes := local.Hour()*60 + local.Minute()
+	return minutesInRange(minutes, w.startMin, w.endMin)
+}
+
+func weekdayInRange(day, start, end time.Weekday) bool {
+	if start <= end {
+		return day >= start && day <= end
+	}
+	// The range wraps around the end of the week, e.g. Fri-Mon.
+	return day >= start || day <= end
+}
+
+func minutesInRange(minutes, start, end int) bool {
+	if start <= end {
+		return minutes >= start && minutes <= end
+	}
+	// The range wraps around midnight, e.g. 22:00-06:00.
+	return minutes >= start || minutes <= end
+}
+
+// isBulkOperation reports whether opts describes a bulk operation, i.e. one
+// that can apply to more than one project in a single run, as opposed to a
+// single project looked up by number, owner, or ID.
+func isBulkOperation(opts templateOpts) bool {
+	return opts.fromFile != "" ||
+		opts.rangeExpr != "" ||
+		opts.search != "" ||
+		opts.allOrgs ||
+		opts.myOrgs ||
+		opts.enterprise != "" ||
+		opts.descriptionContains != "" ||
+		opts.fieldValue != "" ||
+		opts.fieldOption != "" ||
+		opts.restoreState != "" ||
+		opts.undoLog != "" ||
+		opts.allLinked
+}
+
+// checkAllowWindow refuses a bulk operation run outside opts.allowWindow,
+// unless opts.force is set. It is a no-op for non-bulk operations (a single
+// project by number or ID) and when --allow-window was not given, regardless
+// of --force.
+func checkAllowWindow(config templateConfig) error {
+	if config.opts.allowWindow == "" {
+		return nil
+	}
+	if !isBulkOperation(config.opts) {
+		return nil
+	}
+	if config.opts.force {
+		return nil
+	}
+
+	window, err := parseAllowWindow(config.opts.allowWindow)
+	if err != nil {
+		return err
+	}
+
+	now := config.opts.now()
+	if window.contains(now) {
+		return nil
+	}
+
+	return fmt.Errorf("bulk operation refused: current time %s is outside --allow-window %q; pass --force to override", now.In(window.loc).Format(time.RFC3339), config.opts.allowWindow)
+}
@@ -0,0 +1,146 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAllowWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			spec: "Mon-Fri 09:00-17:00 America/New_York",
+		},
+		{
+			name: "valid wraparound",
+			spec: "Fri-Mon 22:00-06:00 UTC",
+		},
+		{
+			name:    "too few fields",
+			spec:    "Mon-Fri 09:00-17:00",
+			wantErr: `invalid --allow-window "Mon-Fri 09:00-17:00": expected "<start day>-<end day> <start time>-<end time> <zoneinfo location>"`,
+		},
+		{
+			name:    "malformed day range",
+			spec:    "Monday 09:00-17:00 UTC",
+			wantErr: `invalid --allow-window day range "Monday": expected "<start day>-<end day>", e.g. "Mon-Fri"`,
+		},
+		{
+			name:    "unknown day",
+			spec:    "Mon-Funday 09:00-17:00 UTC",
+			wantErr: `invalid --allow-window day "Funday": expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun`,
+		},
+		{
+			name:    "malformed time range",
+			spec:    "Mon-Fri 0900 UTC",
+			wantErr: `invalid --allow-window time range "0900": expected "<start time>-<end time>", e.g. "09:00-17:00"`,
+		},
+		{
+			name:    "malformed time",
+			spec:    "Mon-Fri 9am-17:00 UTC",
+			wantErr: `invalid --allow-window start time "9am": expected 24-hour "HH:MM"`,
+		},
+		{
+			name:    "unknown location",
+			spec:    "Mon-Fri 09:00-17:00 Nowhere/Place",
+			wantErr: `invalid --allow-window location "Nowhere/Place": unknown time zone Nowhere/Place`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := parseAllowWindow(tt.spec)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, window)
+		})
+	}
+}
+
+func TestAllowWindow_Contains(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	window, err := parseAllowWindow("Mon-Fri 09:00-17:00 America/New_York")
+	assert.NoError(t, err)
+
+	// Tuesday, 10:00 ET: inside the window.
+	assert.True(t, window.contains(time.Date(2024, 1, 2, 10, 0, 0, 0, ny)))
+	// Saturday, 10:00 ET: right day-of-week, wrong day.
+	assert.False(t, window.contains(time.Date(2024, 1, 6, 10, 0, 0, 0, ny)))
+	// Tuesday, 20:00 ET: right day, wrong time.
+	assert.False(t, window.contains(time.Date(2024, 1, 2, 20, 0, 0, 0, ny)))
+	// Tuesday, 10:00 UTC (05:00 ET): outside the window once converted.
+	assert.False(t, window.contains(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)))
+
+	wrap, err := parseAllowWindow("Fri-Mon 22:00-06:00 UTC")
+	assert.NoError(t, err)
+	// Saturday 23:00 UTC: inside the wraparound day and time ranges.
+	assert.True(t, wrap.contains(time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC)))
+	// Wednesday 23:00 UTC: outside the wraparound day range.
+	assert.False(t, wrap.contains(time.Date(2024, 1, 3, 23, 0, 0, 0, time.UTC)))
+	// Saturday 12:00 UTC: right day, outside the wraparound time range.
+	assert.False(t, wrap.contains(time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestIsBulkOperation(t *testing.T) {
+	assert.False(t, isBulkOperation(templateOpts{owner: "github", number: 1}))
+	assert.True(t, isBulkOperation(templateOpts{fromFile: "manifest.json"}))
+	assert.True(t, isBulkOperation(templateOpts{rangeExpr: "1-10"}))
+	assert.True(t, isBulkOperation(templateOpts{search: "org:github"}))
+	assert.True(t, isBulkOperation(templateOpts{allOrgs: true}))
+	assert.True(t, isBulkOperation(templateOpts{myOrgs: true}))
+	assert.True(t, isBulkOperation(templateOpts{enterprise: "acme"}))
+	assert.True(t, isBulkOperation(templateOpts{descriptionContains: "[template]"}))
+	assert.True(t, isBulkOperation(templateOpts{fieldValue: "ExternalID=PRJ-42"}))
+	assert.True(t, isBulkOperation(templateOpts{restoreState: "state.json"}))
+	assert.True(t, isBulkOperation(templateOpts{undoLog: "ops.jsonl"}))
+}
+
+func TestCheckAllowWindow(t *testing.T) {
+	outsideWindow := func() time.Time { return time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC) } // Saturday
+
+	tests := []struct {
+		name    string
+		opts    templateOpts
+		wantErr string
+	}{
+		{
+			name: "no window set",
+			opts: templateOpts{fromFile: "manifest.json", now: outsideWindow},
+		},
+		{
+			name: "non-bulk operation is unaffected",
+			opts: templateOpts{owner: "github", number: 1, allowWindow: "Mon-Fri 09:00-17:00 UTC", now: outsideWindow},
+		},
+		{
+			name: "force overrides",
+			opts: templateOpts{fromFile: "manifest.json", allowWindow: "Mon-Fri 09:00-17:00 UTC", force: true, now: outsideWindow},
+		},
+		{
+			name:    "refused outside window",
+			opts:    templateOpts{fromFile: "manifest.json", allowWindow: "Mon-Fri 09:00-17:00 UTC", now: outsideWindow},
+			wantErr: `bulk operation refused: current time 2024-01-06T10:00:00Z is outside --allow-window "Mon-Fri 09:00-17:00 UTC"; pass --force to override`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAllowWindow(templateConfig{opts: tt.opts})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}